@@ -0,0 +1,296 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/nmante/validator/compare"
+	"github.com/nmante/validator/funcs"
+	"github.com/nmante/validator/transform"
+	"github.com/nmante/validator/types"
+)
+
+// RuleBuilder builds a Rule with a fluent API. Value type methods like String and Int, and
+// conversions like AsInt, pick the transformer and comparer used by the constraints that follow,
+// so constraint arguments are type checked when the rule is built rather than when it's validated
+type RuleBuilder struct {
+	rule        Rule
+	transformer transform.Interface
+	comparer    compare.Interface
+	_type       reflect.Type
+	err         error
+}
+
+// Key starts building a Rule for a key
+func Key(key string) *RuleBuilder {
+	return &RuleBuilder{rule: Rule{Key: key, Funcs: []funcs.Func{}}}
+}
+
+// BuildRules builds every builder, returning the first error
+func BuildRules(builders ...*RuleBuilder) ([]Rule, error) {
+	rules := []Rule{}
+	for _, b := range builders {
+		rule, err := b.Build()
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// Build returns the Rule, or the first error found while building it
+func (b *RuleBuilder) Build() (Rule, error) {
+	if b.err != nil {
+		return Rule{}, b.err
+	}
+
+	return b.rule, nil
+}
+
+// MustBuild is like Build but panics if there was an error
+func (b *RuleBuilder) MustBuild() Rule {
+	rule, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+
+	return rule
+}
+
+// Required marks the key as required
+func (b *RuleBuilder) Required() *RuleBuilder {
+	b.rule.IsRequired = true
+	return b
+}
+
+// Parallel runs the rule's Funcs in parallel
+func (b *RuleBuilder) Parallel() *RuleBuilder {
+	b.rule.EnableParallel = true
+	return b
+}
+
+// Bail stops running the rule's Funcs after the first failure
+func (b *RuleBuilder) Bail() *RuleBuilder {
+	b.rule.Bail = true
+	return b
+}
+
+// Deprecated marks the key as deprecated
+func (b *RuleBuilder) Deprecated(d Deprecation) *RuleBuilder {
+	b.rule.Deprecation = &d
+	return b
+}
+
+// Description documents the key
+func (b *RuleBuilder) Description(description string) *RuleBuilder {
+	b.rule.Description = description
+	return b
+}
+
+// Default documents the value the key takes when it isn't sent
+func (b *RuleBuilder) Default(value interface{}) *RuleBuilder {
+	b.rule.Default = value
+	return b
+}
+
+// Examples documents example values of the key
+func (b *RuleBuilder) Examples(examples ...interface{}) *RuleBuilder {
+	b.rule.Examples = append(b.rule.Examples, examples...)
+	return b
+}
+
+// Warning reports the rule's failures as warnings, so they don't make the response invalid
+func (b *RuleBuilder) Warning() *RuleBuilder {
+	b.rule.Severity = funcs.SeverityWarning
+	return b
+}
+
+// String checks the value is a string
+func (b *RuleBuilder) String() *RuleBuilder {
+	return b.valueType(funcs.IsType(types.String), types.String, compare.String)
+}
+
+// Int checks the value is an int
+func (b *RuleBuilder) Int() *RuleBuilder {
+	return b.valueType(funcs.IsInt, types.Int, compare.Int)
+}
+
+// Float64 checks the value is a float64
+func (b *RuleBuilder) Float64() *RuleBuilder {
+	return b.valueType(funcs.IsFloat64, types.Float64, compare.Float64)
+}
+
+// AsInt checks a string value holds an int, and compares it as an int from then on
+func (b *RuleBuilder) AsInt() *RuleBuilder {
+	return b.conversion("AsInt", transform.StringToInt, types.Int, compare.Int)
+}
+
+// AsUint checks a string value holds an unsigned int, and compares it as a uint64 from then on
+func (b *RuleBuilder) AsUint() *RuleBuilder {
+	return b.conversion("AsUint", transform.StringToUint, types.Uint64, compare.Uint64)
+}
+
+// AsFloat64 checks a string value holds a float, and compares it as a float64 from then on
+func (b *RuleBuilder) AsFloat64() *RuleBuilder {
+	return b.conversion("AsFloat64", transform.StringToFloat64, types.Float64, compare.Float64)
+}
+
+// AsBool checks a string value holds a boolean
+func (b *RuleBuilder) AsBool() *RuleBuilder {
+	return b.conversion("AsBool", transform.StringToBool, types.Bool, nil)
+}
+
+// Between checks the value is between lower and upper. Both must have the value's type
+func (b *RuleBuilder) Between(lower interface{}, upper interface{}) *RuleBuilder {
+	if !b.isComparable("Between", lower, upper) {
+		return b
+	}
+
+	return b.Func(b.typed(funcs.IsBetween(b.transformer, b.comparer, lower, upper)))
+}
+
+// Equal checks the value is equal to right, which must have the value's type
+func (b *RuleBuilder) Equal(right interface{}) *RuleBuilder {
+	if !b.isComparable("Equal", right) {
+		return b
+	}
+
+	return b.Func(b.typed(funcs.IsEqual(b.transformer, b.comparer, right)))
+}
+
+// Length checks the length of the value
+func (b *RuleBuilder) Length(length int) *RuleBuilder {
+	return b.Func(funcs.IsLength(length))
+}
+
+// LengthBetween checks the length of the value is within a range
+func (b *RuleBuilder) LengthBetween(lower int, upper int) *RuleBuilder {
+	if lower > upper {
+		return b.fail("LengthBetween lower bound %d is greater than upper bound %d", lower, upper)
+	}
+
+	return b.Func(funcs.IsLengthBetween(lower, upper))
+}
+
+// Email checks a string value is an email address
+func (b *RuleBuilder) Email() *RuleBuilder {
+	if b._type != types.String || b.transformer != transform.None {
+		return b.fail("Email must follow String")
+	}
+
+	return b.Func(b.typed(funcs.String.IsEmail))
+}
+
+// Func adds a custom Func to the rule
+func (b *RuleBuilder) Func(f funcs.Func) *RuleBuilder {
+	b.rule.Funcs = append(b.rule.Funcs, f)
+	return b
+}
+
+// Message replaces the error message of the Func added last
+func (b *RuleBuilder) Message(message string) *RuleBuilder {
+	last := len(b.rule.Funcs) - 1
+	if last < 0 {
+		return b.fail("Message must follow a constraint")
+	}
+
+	f := b.rule.Funcs[last]
+	withMessage := func(v interface{}) (funcs.Response, error) {
+		response, err := f(v)
+		if err == nil && !response.IsValid {
+			response.Error = message
+		}
+
+		return response, err
+	}
+
+	if meta, ok := funcs.Describe(f); ok {
+		meta.Description = message
+		b.rule.Funcs[last] = funcs.WithMeta(meta, withMessage)
+	} else {
+		b.rule.Funcs[last] = withMessage
+	}
+
+	return b
+}
+
+func (b *RuleBuilder) valueType(f funcs.Func, _type reflect.Type, comparer compare.Interface) *RuleBuilder {
+	if b._type != nil {
+		return b.fail("value type is already %s", b._type)
+	}
+
+	b.transformer = transform.None
+	b.comparer = comparer
+	b._type = _type
+
+	return b.Func(f)
+}
+
+func (b *RuleBuilder) conversion(method string, transformer transform.Interface, _type reflect.Type, comparer compare.Interface) *RuleBuilder {
+	if b._type != types.String || b.transformer != transform.None {
+		return b.fail("%s must follow String", method)
+	}
+
+	// the conversion check only runs on strings, so a value that isn't a string is only reported once
+	f := b.typed(funcs.IsTransformableTo(transformer, _type))
+
+	b.transformer = transformer
+	b.comparer = comparer
+	b._type = _type
+
+	return b.Func(f)
+}
+
+// typed wraps a constraint so it passes values that aren't of the value type. The value type or
+// conversion check already reports them as invalid, so the constraint doesn't need to
+func (b *RuleBuilder) typed(f funcs.Func) funcs.Func {
+	transformer, _type := b.transformer, b._type
+	guarded := func(v interface{}) (funcs.Response, error) {
+		value, err := transformer.Transform(v)
+		if err != nil || reflect.TypeOf(value) != _type {
+			return funcs.Response{IsValid: true}, nil
+		}
+
+		return f(v)
+	}
+
+	if meta, ok := funcs.Describe(f); ok {
+		return funcs.WithMeta(meta, guarded)
+	}
+
+	return guarded
+}
+
+// isComparable checks the value can be compared, and that every argument has the value's type
+func (b *RuleBuilder) isComparable(method string, args ...interface{}) bool {
+	if b._type == nil {
+		b.fail("%s must follow a value type", method)
+		return false
+	}
+
+	if b.comparer == nil {
+		b.fail("%s can't compare %s values", method, b._type)
+		return false
+	}
+
+	for _, arg := range args {
+		if reflect.TypeOf(arg) != b._type {
+			b.fail("%s arguments must be %s, not %T", method, b._type, arg)
+			return false
+		}
+	}
+
+	return true
+}
+
+// fail records the first error found while building
+func (b *RuleBuilder) fail(format string, args ...interface{}) *RuleBuilder {
+	if b.err == nil {
+		b.err = fmt.Errorf("%s: %s", b.rule.Key, fmt.Sprintf(format, args...))
+	}
+
+	return b
+}
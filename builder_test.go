@@ -0,0 +1,96 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRuleBuilder(t *testing.T) {
+	rules, err := BuildRules(
+		Key("page_size").Required().String().AsInt().Equal(50).Message("must be 50"),
+		Key("email").String().Email().LengthBetween(3, 64),
+		Key("ratio").Float64(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validator, _ := New(rules)
+
+	r, err := validator.Validate(map[string]interface{}{
+		"page_size": "51",
+		"email":     "nii.mante@buzzfeed.com",
+		"ratio":     1,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if m := r.Errors["page_size"]; len(m) != 1 || m[0] != "must be 50" {
+		t.Errorf("page_size should fail with a custom message. Errors: %q", m)
+	}
+
+	if _, ok := r.Errors["email"]; ok {
+		t.Errorf("email should be valid. Errors: %q", r.Errors["email"])
+	}
+
+	if m := r.Errors["ratio"]; len(m) != 1 {
+		t.Errorf("ratio should fail because it isn't a float64. Errors: %q", m)
+	}
+}
+
+func TestRuleBuilderConstraints(t *testing.T) {
+	validator, _ := New([]Rule{
+		Key("page_size").Int().Between(1, 100).MustBuild(),
+		Key("limit").String().AsInt().Between(1, 100).MustBuild(),
+	})
+
+	builderTests := []struct {
+		key     string
+		value   interface{}
+		message string
+	}{
+		{key: "page_size", value: 50},
+		{key: "page_size", value: 1},
+		{key: "page_size", value: 100},
+		{key: "page_size", value: 500, message: "must be between 1 and 100"},
+		{key: "page_size", value: 0, message: "must be between 1 and 100"},
+		{key: "page_size", value: "50", message: "must be a int"},
+		{key: "limit", value: "50"},
+		{key: "limit", value: "500", message: "must be between 1 and 100"},
+		{key: "limit", value: "abc", message: "abc not transformable to int"},
+		{key: "limit", value: 50, message: "must be a string"},
+	}
+
+	for _, test := range builderTests {
+		r, err := validator.Validate(map[string]interface{}{test.key: test.value})
+		if err != nil {
+			t.Errorf("%s %v: there should be no error. It is %v", test.key, test.value, err)
+			continue
+		}
+
+		messages := r.Errors[test.key]
+		if test.message == "" && len(messages) != 0 || test.message != "" && !reflect.DeepEqual(messages, []string{test.message}) {
+			t.Errorf("%s %v: errors should be %q. They are %q", test.key, test.value, test.message, messages)
+		}
+	}
+}
+
+func TestRuleBuilderErrors(t *testing.T) {
+	builderTests := []*RuleBuilder{
+		Key("page_size").String().AsInt().Between(int64(1), int64(100)),
+		Key("page_size").String().AsFloat64().Equal(1),
+		Key("page_size").Between(1, 100),
+		Key("page_size").Int().AsInt(),
+		Key("page_size").Int().String(),
+		Key("flag").String().AsBool().Equal(true),
+		Key("name").LengthBetween(10, 1),
+		Key("name").Message("must be set"),
+	}
+
+	for _, b := range builderTests {
+		if _, err := b.Build(); err == nil {
+			t.Errorf("There should be an error building %s", b.rule.Key)
+		}
+	}
+}
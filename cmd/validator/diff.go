@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/nmante/validator"
+	"github.com/nmante/validator/diff"
+)
+
+// errBreaking is returned when the new rules have breaking changes
+var errBreaking = errors.New("breaking changes found")
+
+func runDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	allowBreaking := flags.Bool("allow-breaking", false, "exit 0 even if there are breaking changes")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 2 {
+		return errors.New("expected an old and a new rules file")
+	}
+
+	report, err := diffFiles(flags.Arg(0), flags.Arg(1), os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	if report.Breaking && !*allowBreaking {
+		return errBreaking
+	}
+
+	return nil
+}
+
+// diffFiles writes the diff of two rules files, written as JSON by Validator.Describe, to w
+func diffFiles(oldPath string, newPath string, w io.Writer) (diff.Report, error) {
+	oldDesc, err := readDescription(oldPath)
+	if err != nil {
+		return diff.Report{}, err
+	}
+
+	newDesc, err := readDescription(newPath)
+	if err != nil {
+		return diff.Report{}, err
+	}
+
+	report, err := diff.Descriptions(oldDesc, newDesc)
+	if err != nil {
+		return diff.Report{}, err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return report, encoder.Encode(report)
+}
+
+func readDescription(path string) (validator.Description, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return validator.Description{}, err
+	}
+	defer f.Close()
+
+	return diff.Read(f)
+}
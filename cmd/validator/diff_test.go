@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nmante/validator"
+	"github.com/nmante/validator/diff"
+)
+
+func TestDiff(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, rules ...validator.Rule) string {
+		v, err := validator.New(rules)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		out, _ := json.Marshal(v.Describe())
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, out, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		return path
+	}
+
+	old := write("old.json", validator.Key("qty").Int().Between(1, 100).MustBuild())
+	widened := write("widened.json", validator.Key("qty").Int().Between(0, 100).MustBuild())
+	narrowed := write("narrowed.json", validator.Key("qty").Int().Between(1, 10).MustBuild())
+
+	buf := &bytes.Buffer{}
+	if _, err := diffFiles(old, widened, buf); err != nil {
+		t.Fatal(err)
+	}
+
+	report := diff.Report{}
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Breaking || len(report.Changes) != 1 || report.Changes[0].Kind != diff.RangeWidened {
+		t.Errorf("Report should have a non-breaking widened range. It is %s", buf)
+	}
+
+	diffTests := []struct {
+		args []string
+		err  error
+	}{
+		{args: []string{old, widened}},
+		{args: []string{old, narrowed}, err: errBreaking},
+		{args: []string{"-allow-breaking", old, narrowed}},
+	}
+
+	stdout := os.Stdout
+	os.Stdout, _ = os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	defer func() { os.Stdout = stdout }()
+
+	for _, test := range diffTests {
+		if err := runDiff(test.args); err != test.err {
+			t.Errorf("%v: error should be %v. It is %v", test.args, test.err, err)
+		}
+	}
+
+	if err := runDiff([]string{old}); err == nil {
+		t.Error("A missing rules file should be an error")
+	}
+}
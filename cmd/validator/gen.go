@@ -0,0 +1,562 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const generatedHeader = "// Code generated by validator gen. DO NOT EDIT."
+
+var (
+	ErrNoTypes = errors.New("no structs with validate tags found")
+)
+
+func runGen(args []string) error {
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	typeNames := flags.String("type", "", "comma separated list of types to generate Validate methods for. Defaults to every struct with validate tags")
+	output := flags.String("output", "", "output file. Defaults to <package>_validate.go in dir")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	dir := "."
+	if flags.NArg() > 0 {
+		dir = flags.Arg(0)
+	}
+
+	names := []string{}
+	if *typeNames != "" {
+		names = strings.Split(*typeNames, ",")
+	}
+
+	pkgName, src, err := generate(dir, names)
+	if err != nil {
+		return err
+	}
+
+	path := *output
+	if path == "" {
+		path = filepath.Join(dir, pkgName+"_validate.go")
+	}
+
+	return os.WriteFile(path, src, 0644)
+}
+
+// generate returns the package name and the source of Validate methods for the structs in dir
+func generate(dir string, typeNames []string) (string, []byte, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", nil, err
+	}
+
+	fset := token.NewFileSet()
+	pkgNames := map[string]bool{}
+	var files []*ast.File
+	var pkgName string
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			return "", nil, err
+		}
+
+		pkgName = file.Name.Name
+		pkgNames[pkgName] = true
+		if !isGenerated(file) {
+			files = append(files, file)
+		}
+	}
+
+	if len(pkgNames) != 1 {
+		return "", nil, fmt.Errorf("expected 1 package in %s, found %d", dir, len(pkgNames))
+	}
+
+	// Type errors are ignored, since the package may not build until its Validate methods exist
+	config := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {},
+	}
+
+	pkg, _ := config.Check(pkgName, fset, files, nil)
+
+	g := &generator{pkg: pkg, imports: map[string]bool{}, targets: map[string]*types.Struct{}}
+	if err := g.findTargets(typeNames); err != nil {
+		return "", nil, err
+	}
+
+	src, err := g.generate()
+	return pkgName, src, err
+}
+
+func isGenerated(file *ast.File) bool {
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			if comment.Text == generatedHeader {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+type generator struct {
+	pkg     *types.Package
+	buf     bytes.Buffer
+	imports map[string]bool
+	// targets are the structs that get a Validate method, by type name
+	targets map[string]*types.Struct
+}
+
+// findTargets finds the named structs to generate. If typeNames is empty, every struct with
+// validate tags is generated
+func (g *generator) findTargets(typeNames []string) error {
+	scope := g.pkg.Scope()
+
+	if len(typeNames) == 0 {
+		for _, name := range scope.Names() {
+			if s, ok := structOf(scope.Lookup(name)); ok && hasTags(s) {
+				g.targets[name] = s
+			}
+		}
+
+		if len(g.targets) == 0 {
+			return ErrNoTypes
+		}
+
+		return nil
+	}
+
+	for _, name := range typeNames {
+		s, ok := structOf(scope.Lookup(name))
+		if !ok {
+			return fmt.Errorf("%s is not a struct type", name)
+		}
+
+		g.targets[name] = s
+	}
+
+	return nil
+}
+
+func structOf(obj types.Object) (*types.Struct, bool) {
+	tn, ok := obj.(*types.TypeName)
+	if !ok {
+		return nil, false
+	}
+
+	s, ok := tn.Type().Underlying().(*types.Struct)
+	return s, ok
+}
+
+func hasTags(s *types.Struct) bool {
+	for i := 0; i < s.NumFields(); i++ {
+		if _, ok := reflect.StructTag(s.Tag(i)).Lookup("validate"); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g *generator) generate() ([]byte, error) {
+	names := []string{}
+	for name := range g.targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var body bytes.Buffer
+	for _, name := range names {
+		g.buf.Reset()
+		if err := g.generateType(name, g.targets[name]); err != nil {
+			return nil, err
+		}
+
+		body.Write(g.buf.Bytes())
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "%s\n\npackage %s\n\nimport (\n", generatedHeader, g.pkg.Name())
+
+	g.imports["github.com/nmante/validator"] = true
+	imports := []string{}
+	for path := range g.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+
+	for _, path := range imports {
+		fmt.Fprintf(&src, "\t%q\n", path)
+	}
+
+	src.WriteString(")\n")
+	src.Write(body.Bytes())
+
+	return format.Source(src.Bytes())
+}
+
+func (g *generator) generateType(name string, s *types.Struct) error {
+	g.printf("\n// Validate validates %s against its validate tags\n", name)
+	g.printf("func (s %s) Validate() (validator.Response, error) {\n", name)
+	g.printf("r := validator.Response{Errors: map[string][]string{}, IsValid: true}\n")
+
+	for i := 0; i < s.NumFields(); i++ {
+		field := s.Field(i)
+		tag := reflect.StructTag(s.Tag(i))
+
+		if err := g.generateField(field, tag); err != nil {
+			return fmt.Errorf("%s.%s: %s", name, field.Name(), err)
+		}
+	}
+
+	g.printf("return r, nil\n}\n")
+	return nil
+}
+
+func (g *generator) generateField(field *types.Var, tag reflect.StructTag) error {
+	validate, hasValidate := tag.Lookup("validate")
+	if validate == "-" || !field.Exported() {
+		return nil
+	}
+
+	key := keyOf(field, tag)
+	value := "s." + field.Name()
+	t := field.Type()
+
+	if t == nil || t == types.Typ[types.Invalid] {
+		return errors.New("can't resolve the field's type")
+	}
+
+	constraints := []string{}
+	if hasValidate && validate != "" {
+		constraints = strings.Split(validate, ",")
+	}
+
+	for _, constraint := range constraints {
+		if err := g.generateConstraint(key, value, t, constraint); err != nil {
+			return err
+		}
+	}
+
+	g.generateNested(key, value, t)
+	return nil
+}
+
+// keyOf returns the field's json name, or the field name if it has none
+func keyOf(field *types.Var, tag reflect.StructTag) string {
+	if name := strings.Split(tag.Get("json"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+
+	return field.Name()
+}
+
+func (g *generator) generateConstraint(key string, value string, t types.Type, constraint string) error {
+	name, arg, _ := strings.Cut(strings.TrimSpace(constraint), "=")
+
+	switch name {
+	case "required":
+		isZero, ok := zeroCheck(value, t)
+		if !ok {
+			return fmt.Errorf("%s needs a type with a nil or zero value to check", name)
+		}
+
+		g.printf("if %s {\nr.AddError(%q, \"is required\")\n}\n", isZero, key)
+		return nil
+	case "len":
+		if !hasLen(t) {
+			return fmt.Errorf("%s needs a string, slice, array or map", name)
+		}
+
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return fmt.Errorf("%s needs an integer argument", name)
+		}
+
+		g.fail(fmt.Sprintf("len(%s) != %d", value, n), key, fmt.Sprintf("Must have length %d", n))
+		return nil
+	case "lenbetween":
+		if !hasLen(t) {
+			return fmt.Errorf("%s needs a string, slice, array or map", name)
+		}
+
+		bounds := strings.Split(arg, ":")
+		if len(bounds) != 2 {
+			return fmt.Errorf("%s needs lower:upper arguments", name)
+		}
+
+		lower, lerr := strconv.Atoi(bounds[0])
+		upper, uerr := strconv.Atoi(bounds[1])
+		if lerr != nil || uerr != nil {
+			return fmt.Errorf("%s needs integer arguments", name)
+		}
+
+		condition := fmt.Sprintf("len(%s) > %d", value, upper)
+		if lower > 0 {
+			condition = fmt.Sprintf("len(%s) < %d || %s", value, lower, condition)
+		}
+
+		g.fail(condition, key, fmt.Sprintf("Must be between length %d and %d", lower, upper))
+		return nil
+	case "email":
+		if !isKind(t, types.IsString) {
+			return fmt.Errorf("%s needs a string", name)
+		}
+
+		if t != types.Typ[types.String] {
+			value = "string(" + value + ")"
+		}
+
+		return g.call(key, fmt.Sprintf("funcs.String.IsEmail(%s)", value))
+	case "min", "max", "between":
+		if !isKind(t, types.IsOrdered) {
+			return fmt.Errorf("%s needs an ordered type", name)
+		}
+
+		args := strings.Split(arg, ":")
+		if (name == "between") != (len(args) == 2) || len(args) > 2 {
+			return fmt.Errorf("%s has the wrong number of arguments", name)
+		}
+
+		literals, err := literalsOf(t, args)
+		if err != nil {
+			return fmt.Errorf("%s: %s", name, err)
+		}
+
+		switch name {
+		case "min":
+			g.fail(fmt.Sprintf("%s < %s", value, literals[0].code), key, "must be at least "+literals[0].text)
+		case "max":
+			g.fail(fmt.Sprintf("%s > %s", value, literals[0].code), key, "must be at most "+literals[0].text)
+		default:
+			g.fail(fmt.Sprintf("%s < %s || %s > %s", value, literals[0].code, value, literals[1].code), key, fmt.Sprintf("must be between %s and %s", literals[0].text, literals[1].text))
+		}
+
+		return nil
+	case "oneof":
+		if !isKind(t, types.IsOrdered|types.IsBoolean) {
+			return fmt.Errorf("%s needs a basic type", name)
+		}
+
+		literals, err := literalsOf(t, strings.Fields(arg))
+		if err != nil {
+			return fmt.Errorf("%s: %s", name, err)
+		}
+
+		if len(literals) == 0 {
+			return fmt.Errorf("%s needs at least one value", name)
+		}
+
+		conditions, texts := make([]string, len(literals)), make([]string, len(literals))
+		for i, literal := range literals {
+			conditions[i] = fmt.Sprintf("%s != %s", value, literal.code)
+			texts[i] = literal.text
+		}
+
+		g.fail(strings.Join(conditions, " && "), key, "must be one of "+strings.Join(texts, ", "))
+		return nil
+	}
+
+	return fmt.Errorf("unknown constraint %q", name)
+}
+
+// fail adds message to the response when condition is true. The messages match the ones the
+// equivalent funcs report
+func (g *generator) fail(condition string, key string, message string) {
+	g.printf("if %s {\nr.AddError(%q, %q)\n}\n", condition, key, message)
+}
+
+// call adds the result of a funcs call to the response
+func (g *generator) call(key string, call string) error {
+	g.imports["github.com/nmante/validator/funcs"] = true
+	g.printf("if fr, err := %s; err != nil {\nreturn validator.Response{}, err\n} else if !fr.IsValid {\nr.AddError(%q, fr.Error)\n}\n", call, key)
+	return nil
+}
+
+// generateNested merges the errors of nested structs, and slices or arrays of structs
+func (g *generator) generateNested(key string, value string, t types.Type) {
+	switch u := t.Underlying().(type) {
+	case *types.Pointer:
+		if g.isValidatable(u.Elem()) {
+			g.printf("if %s != nil {\n", value)
+			g.merge(fmt.Sprintf("%q", key), value)
+			g.printf("}\n")
+		}
+	case *types.Slice, *types.Array:
+		elem := u.(interface{ Elem() types.Type }).Elem()
+		pointer, isPointer := elem.Underlying().(*types.Pointer)
+		if isPointer {
+			elem = pointer.Elem()
+		}
+
+		if g.isValidatable(elem) {
+			g.imports["strconv"] = true
+			g.printf("for i := range %s {\n", value)
+			if isPointer {
+				g.printf("if %s[i] != nil {\n", value)
+			}
+			g.merge(fmt.Sprintf("%q + strconv.Itoa(i) + \"]\"", key+"["), value+"[i]")
+			if isPointer {
+				g.printf("}\n")
+			}
+			g.printf("}\n")
+		}
+	default:
+		if g.isValidatable(t) {
+			g.merge(fmt.Sprintf("%q", key), value)
+		}
+	}
+}
+
+func (g *generator) merge(key string, value string) {
+	g.printf("if nr, err := %s.Validate(); err != nil {\nreturn validator.Response{}, err\n} else {\nr.Merge(%s, nr)\n}\n", value, key)
+}
+
+// isValidatable checks if a type has a Validate method returning a validator.Response, or will have
+// one once generated. Validate methods of funcs.Interface return a funcs.Response, so they can't be
+// merged
+func (g *generator) isValidatable(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+
+	if named.Obj().Pkg() == g.pkg {
+		if _, ok := g.targets[named.Obj().Name()]; ok {
+			return true
+		}
+	}
+
+	obj, _, _ := types.LookupFieldOrMethod(named, false, named.Obj().Pkg(), "Validate")
+	method, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+
+	signature := method.Type().(*types.Signature)
+	results := signature.Results()
+	if signature.Params().Len() != 0 || results.Len() != 2 || !types.Identical(results.At(1).Type(), types.Universe.Lookup("error").Type()) {
+		return false
+	}
+
+	response, ok := results.At(0).Type().(*types.Named)
+	return ok && response.Obj().Name() == "Response" && response.Obj().Pkg() != nil && response.Obj().Pkg().Path() == "github.com/nmante/validator"
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// zeroCheck returns an expression that's true when value is its type's zero value
+func zeroCheck(value string, t types.Type) (string, bool) {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsString != 0:
+			return value + ` == ""`, true
+		case u.Info()&types.IsBoolean != 0:
+			return "!" + value, true
+		default:
+			return value + " == 0", true
+		}
+	case *types.Slice, *types.Map:
+		return "len(" + value + ") == 0", true
+	case *types.Pointer, *types.Interface, *types.Chan, *types.Signature:
+		return value + " == nil", true
+	}
+
+	return "", false
+}
+
+func hasLen(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Slice, *types.Array, *types.Map:
+		return true
+	}
+
+	return isKind(t, types.IsString)
+}
+
+func isKind(t types.Type, info types.BasicInfo) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && basic.Info()&info != 0
+}
+
+// literal is a tag argument as Go code, and as text for error messages
+type literal struct {
+	code string
+	text string
+}
+
+// literalsOf converts tag arguments to Go literals of type t. Integer arguments must fit in t
+func literalsOf(t types.Type, args []string) ([]literal, error) {
+	basic, _ := t.Underlying().(*types.Basic)
+
+	literals := make([]literal, len(args))
+	for i, arg := range args {
+		switch {
+		case isKind(t, types.IsString):
+			literals[i] = literal{code: strconv.Quote(arg), text: arg}
+		case isKind(t, types.IsBoolean):
+			b, err := strconv.ParseBool(arg)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a boolean", arg)
+			}
+			literals[i] = literal{code: strconv.FormatBool(b), text: strconv.FormatBool(b)}
+		case isKind(t, types.IsUnsigned):
+			n, err := strconv.ParseUint(arg, 10, bitSize(basic))
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a %s", arg, basic.Name())
+			}
+			literals[i] = literal{code: strconv.FormatUint(n, 10), text: strconv.FormatUint(n, 10)}
+		case isKind(t, types.IsInteger):
+			n, err := strconv.ParseInt(arg, 10, bitSize(basic))
+			if err != nil {
+				return nil, fmt.Errorf("%q is not a %s", arg, basic.Name())
+			}
+			literals[i] = literal{code: strconv.FormatInt(n, 10), text: strconv.FormatInt(n, 10)}
+		default:
+			f, err := strconv.ParseFloat(arg, bitSize(basic))
+			if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+				return nil, fmt.Errorf("%q is not a finite number", arg)
+			}
+			literals[i] = literal{code: strconv.FormatFloat(f, 'g', -1, bitSize(basic)), text: fmt.Sprint(f)}
+		}
+	}
+
+	return literals, nil
+}
+
+// bitSize returns the size of a numeric type in bits. int, uint and uintptr are assumed to be 64 bits
+func bitSize(basic *types.Basic) int {
+	switch basic.Kind() {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32, types.Float32:
+		return 32
+	}
+
+	return 64
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGenerateGolden(t *testing.T) {
+	goldenTests := []struct {
+		dir       string
+		typeNames []string
+		golden    string
+	}{
+		{dir: "testdata/order", golden: "testdata/order.golden"},
+		{dir: "testdata/order", typeNames: []string{"Address"}, golden: "testdata/address.golden"},
+	}
+
+	for _, test := range goldenTests {
+		_, src, err := generate(test.dir, test.typeNames)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if *update {
+			if err := os.WriteFile(test.golden, src, 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		golden, err := os.ReadFile(test.golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(src, golden) {
+			t.Errorf("Generated source for %s doesn't match %s. Run go test -update to update it.\n%s", test.dir, test.golden, src)
+		}
+	}
+}
+
+func TestGenerateCompiles(t *testing.T) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go isn't installed")
+	}
+
+	dir, err := filepath.Abs("testdata/order")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden, err := filepath.Abs("testdata/order.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the golden file is added to the package with an overlay, so testdata isn't written to
+	overlay, _ := json.Marshal(map[string]interface{}{
+		"Replace": map[string]string{filepath.Join(dir, "order_validate.go"): golden},
+	})
+
+	overlayPath := filepath.Join(t.TempDir(), "overlay.json")
+	if err := os.WriteFile(overlayPath, overlay, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(goBin, "vet", "-overlay", overlayPath, "./testdata/order")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("Generated source for testdata/order doesn't compile: %s\n%s", err, out)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	errorTests := []struct {
+		src       string
+		typeNames []string
+	}{
+		{src: "type T struct {\n\tN int `validate:\"email\"`\n}"},
+		{src: "type T struct {\n\tN int `validate:\"between=1\"`\n}"},
+		{src: "type T struct {\n\tN int `validate:\"min=abc\"`\n}"},
+		{src: "type T struct {\n\tN int `validate:\"len=2\"`\n}"},
+		{src: "type T struct {\n\tN int `validate:\"min=1.5\"`\n}"},
+		{src: "type T struct {\n\tN uint `validate:\"min=-1\"`\n}"},
+		{src: "type T struct {\n\tN int8 `validate:\"max=200\"`\n}"},
+		{src: "type T struct {\n\tN float64 `validate:\"min=inf\"`\n}"},
+		{src: "type T struct {\n\tN float64 `validate:\"max=NaN\"`\n}"},
+		{src: "type T struct {\n\tN float32 `validate:\"max=1e39\"`\n}"},
+		{src: "type T struct {\n\tN int `validate:\"unknown\"`\n}"},
+		{src: "type T struct {\n\tN struct{} `validate:\"required\"`\n}"},
+		{src: "type T struct {\n\tN int\n}"},
+		{src: "type T int", typeNames: []string{"T"}},
+	}
+
+	for _, test := range errorTests {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "t.go"), []byte("package t\n\n"+test.src+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, _, err := generate(dir, test.typeNames); err == nil {
+			t.Errorf("There should be an error generating:\n%s", test.src)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Command validator contains tools for working with validators.
+//
+// Usage:
+//
+//	validator gen [-type T1,T2] [-output file] [dir]
+//	validator diff [-allow-breaking] old.json new.json
+//
+// gen writes a Validate method for each struct with `validate` tags. It's meant to be run by
+// go generate:
+//
+//	//go:generate validator gen -type Order
+//
+// diff compares two rules files, written as JSON by Validator.Describe, and prints a JSON report of
+// the changes. It exits with status 1 if any change is breaking, unless -allow-breaking is set
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `Usage:
+
+	validator gen [-type T1,T2] [-output file] [dir]
+	validator diff [-allow-breaking] old.json new.json
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "gen":
+		err = runGen(os.Args[2:])
+	case "diff":
+		err = runDiff(os.Args[2:])
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "validator %s: %s\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
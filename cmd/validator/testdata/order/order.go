@@ -0,0 +1,44 @@
+package order
+
+import "github.com/nmante/validator/funcs"
+
+type Status string
+
+type Order struct {
+	ID       string      `json:"id" validate:"required,lenbetween=1:36"`
+	Email    string      `json:"email" validate:"required,email"`
+	Status   Status      `json:"status" validate:"oneof=draft submitted shipped"`
+	PageSize int         `json:"page_size" validate:"between=1:100"`
+	Discount float64     `json:"discount" validate:"min=0,max=0.5"`
+	Tags     []string    `json:"tags" validate:"lenbetween=0:10"`
+	Shipping Address     `json:"shipping_address"`
+	Billing  *Address    `json:"billing_address"`
+	Items    []LineItem  `json:"items" validate:"required"`
+	Gifts    []*LineItem `json:"gifts"`
+	Priority int8        `json:"priority" validate:"between=-1:5"`
+	Total    Money       `json:"total"`
+	Internal string      `validate:"-"`
+}
+
+type Address struct {
+	Street  string `json:"street" validate:"required"`
+	Country string `json:"country" validate:"len=2"`
+}
+
+type LineItem struct {
+	SKU      string `json:"sku" validate:"required"`
+	Quantity uint   `json:"quantity" validate:"min=1"`
+}
+
+// Money validates itself like funcs.Interface, so its errors can't be merged into an Order's
+type Money struct {
+	Cents int64
+}
+
+func (m Money) Validate() (funcs.Response, error) {
+	return funcs.Response{IsValid: m.Cents >= 0, Error: "must not be negative"}, nil
+}
+
+type unrelated struct {
+	Name string
+}
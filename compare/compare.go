@@ -1,19 +1,53 @@
 package compare
 
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
 var (
 	Default = _default{}
 	Int     = _int{}
 	Float32 = _float32{}
 	Float64 = _float64{}
+	Uint64  = _uint64{}
+	String  = _string{}
 )
 
+var ErrWrongType = errors.New("Comparer can't compare values of this type")
+
 // Comparer compares left & right and returns -1 (less than), 0 (equal), 1 (greater than)
 type Interface interface {
 	Compare(left interface{}, right interface{}) int
 }
 
+// Typed is implemented by comparers that only compare values of one type
+type Typed interface {
+	Type() reflect.Type
+}
+
+// Try compares left and right like comparer.Compare, but returns ErrWrongType rather than panicking
+// when the comparer is Typed and left or right has another type, like float64s with Int
+// Other comparers must handle any values they're given
+func Try(comparer Interface, left interface{}, right interface{}) (int, error) {
+	if typed, ok := comparer.(Typed); ok {
+		for _, value := range []interface{}{left, right} {
+			if reflect.TypeOf(value) != typed.Type() {
+				return 0, fmt.Errorf("%w: %T isn't a %v", ErrWrongType, value, typed.Type())
+			}
+		}
+	}
+
+	return comparer.Compare(left, right), nil
+}
+
 type _default struct{}
 
+func (i _default) Type() reflect.Type {
+	return reflect.TypeOf(int(0))
+}
+
 func (i _default) Compare(left interface{}, right interface{}) int {
 	l := left.(int)
 	r := right.(int)
@@ -29,6 +63,10 @@ func (i _default) Compare(left interface{}, right interface{}) int {
 
 type _int struct{}
 
+func (i _int) Type() reflect.Type {
+	return reflect.TypeOf(int(0))
+}
+
 func (i _int) Compare(left interface{}, right interface{}) int {
 	l := left.(int)
 	r := right.(int)
@@ -44,6 +82,10 @@ func (i _int) Compare(left interface{}, right interface{}) int {
 
 type _float32 struct{}
 
+func (f _float32) Type() reflect.Type {
+	return reflect.TypeOf(float32(0))
+}
+
 func (f _float32) Compare(left interface{}, right interface{}) int {
 	l := left.(float32)
 	r := right.(float32)
@@ -59,6 +101,10 @@ func (f _float32) Compare(left interface{}, right interface{}) int {
 
 type _float64 struct{}
 
+func (f _float64) Type() reflect.Type {
+	return reflect.TypeOf(float64(0))
+}
+
 func (f _float64) Compare(left interface{}, right interface{}) int {
 	l := left.(float64)
 	r := right.(float64)
@@ -71,3 +117,41 @@ func (f _float64) Compare(left interface{}, right interface{}) int {
 
 	return 1
 }
+
+type _uint64 struct{}
+
+func (u _uint64) Type() reflect.Type {
+	return reflect.TypeOf(uint64(0))
+}
+
+func (u _uint64) Compare(left interface{}, right interface{}) int {
+	l := left.(uint64)
+	r := right.(uint64)
+
+	if l < r {
+		return -1
+	} else if l == r {
+		return 0
+	}
+
+	return 1
+}
+
+type _string struct{}
+
+func (s _string) Type() reflect.Type {
+	return reflect.TypeOf("")
+}
+
+func (s _string) Compare(left interface{}, right interface{}) int {
+	l := left.(string)
+	r := right.(string)
+
+	if l < r {
+		return -1
+	} else if l == r {
+		return 0
+	}
+
+	return 1
+}
@@ -1,6 +1,7 @@
 package compare
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -16,6 +17,8 @@ func TestComparers(t *testing.T) {
 		{left: float32(4.5), right: float32(3.55), comparer: Float32, result: 1},
 		{left: float32(1.1), right: float32(1.1), comparer: Float32, result: 0},
 		{left: 1.3, right: 1.5, comparer: Float64, result: -1},
+		{left: uint64(7), right: uint64(3), comparer: Uint64, result: 1},
+		{left: "abc", right: "abd", comparer: String, result: -1},
 	}
 
 	for _, test := range intCompareTests {
@@ -24,3 +27,33 @@ func TestComparers(t *testing.T) {
 		}
 	}
 }
+
+type length struct{}
+
+func (l length) Compare(left interface{}, right interface{}) int {
+	return Int.Compare(len(left.(string)), len(right.(string)))
+}
+
+func TestTry(t *testing.T) {
+	tryTests := []struct {
+		comparer Interface
+		left     interface{}
+		right    interface{}
+		result   int
+		err      error
+	}{
+		{comparer: Int, left: 1, right: 2, result: -1},
+		{comparer: Int, left: 1.0, right: 2.0, err: ErrWrongType},
+		{comparer: Float64, left: 1.0, right: 2, err: ErrWrongType},
+		{comparer: String, left: "b", right: "a", result: 1},
+		{comparer: String, left: nil, right: "a", err: ErrWrongType},
+		{comparer: length{}, left: "ab", right: "a", result: 1},
+	}
+
+	for _, test := range tryTests {
+		result, err := Try(test.comparer, test.left, test.right)
+		if !errors.Is(err, test.err) || result != test.result {
+			t.Errorf("Try(%#v, %#v) should be %d, %v. It is %d, %v", test.left, test.right, test.result, test.err, result, err)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Deprecation marks a rule's key as deprecated. Sending the key adds a warning to the response
+type Deprecation struct {
+	// Replacement is the key to send instead, if there is one
+	Replacement string
+	// Sunset is when the key will stop being accepted, if that's been decided
+	Sunset time.Time
+	// Message replaces the generated warning, like "use the v2 orders API"
+	Message string
+}
+
+// DeprecationUsage is a use of a deprecated key or value, passed to the hook set with
+// OptionDeprecationHook
+type DeprecationUsage struct {
+	Key string
+	// Value is the deprecated value that was sent. It's nil when the key itself is deprecated
+	Value interface{}
+	// Deprecation is set when the key itself is deprecated
+	Deprecation *Deprecation
+}
+
+// DeprecationHook is called for every use of a deprecated key or value, like to count usage. It can
+// be called concurrently by concurrent calls to Validate
+type DeprecationHook func(usage DeprecationUsage)
+
+// warning returns the warning for sending a deprecated key
+func (d *Deprecation) warning() string {
+	if d.Message != "" {
+		return d.Message
+	}
+
+	message := "is deprecated"
+	if d.Replacement != "" {
+		message += fmt.Sprintf(", use %s instead", d.Replacement)
+	}
+
+	if !d.Sunset.IsZero() {
+		message += fmt.Sprintf(", and will be removed on %s", d.Sunset.Format("2006-01-02"))
+	}
+
+	return message
+}
+
+// reportDeprecations adds warnings for a rule's value if its key or the value is deprecated
+func (v *Validator) reportDeprecations(rule Rule, value interface{}, response *Response) {
+	if rule.Deprecation != nil {
+		response.AddWarning(rule.Key, rule.Deprecation.warning())
+		v.useDeprecated(DeprecationUsage{Key: rule.Key, Deprecation: rule.Deprecation})
+	}
+
+	if value == nil || len(rule.DeprecatedValues) == 0 || !reflect.ValueOf(value).Comparable() {
+		return
+	}
+
+	message, ok := rule.DeprecatedValues[value]
+	if !ok {
+		return
+	}
+
+	response.AddWarning(rule.Key, deprecatedValueWarning(value, message))
+	v.useDeprecated(DeprecationUsage{Key: rule.Key, Value: value})
+}
+
+// deprecatedValueWarning returns the warning for sending a deprecated value
+func deprecatedValueWarning(value interface{}, message string) string {
+	if message == "" {
+		return fmt.Sprintf("%v is deprecated", value)
+	}
+
+	return message
+}
+
+func (v *Validator) useDeprecated(usage DeprecationUsage) {
+	if v.deprecationHook != nil {
+		v.deprecationHook(usage)
+	}
+}
+
+// addReplacements returns values with the values of deprecated keys copied to their replacements,
+// so they're validated by the replacements' rules. A replacement that was sent too keeps its own
+// value
+func (v *Validator) addReplacements(values map[string]interface{}) map[string]interface{} {
+	mapped := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		mapped[key] = value
+	}
+
+	for _, rule := range v.rules {
+		d := rule.Deprecation
+		if d == nil || d.Replacement == "" {
+			continue
+		}
+
+		value, ok := mapped[rule.Key]
+		if !ok {
+			continue
+		}
+
+		if _, ok := mapped[d.Replacement]; !ok {
+			mapped[d.Replacement] = value
+		}
+	}
+
+	return mapped
+}
+
+// renameDeprecated returns a copy of values with deprecated keys renamed to their replacements
+func (v *Validator) renameDeprecated(values map[string]interface{}) map[string]interface{} {
+	mapped := v.addReplacements(values)
+	for _, rule := range v.rules {
+		if d := rule.Deprecation; d != nil && d.Replacement != "" {
+			delete(mapped, rule.Key)
+		}
+	}
+
+	return mapped
+}
@@ -0,0 +1,88 @@
+package validator
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nmante/validator/funcs"
+)
+
+func TestDeprecation(t *testing.T) {
+	var mu sync.Mutex
+	usages := []DeprecationUsage{}
+	hook := func(usage DeprecationUsage) {
+		mu.Lock()
+		defer mu.Unlock()
+		usages = append(usages, usage)
+	}
+
+	zip := &Deprecation{Replacement: "postal_code", Sunset: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)}
+	validator, err := New([]Rule{
+		Rule{Key: "postal_code", Funcs: []funcs.Func{funcs.IsLength(5)}},
+		Rule{Key: "zip", Deprecation: zip},
+		Rule{Key: "fax", Deprecation: &Deprecation{Message: "fax is no longer supported"}},
+		Rule{Key: "plan", DeprecatedValues: map[interface{}]string{"legacy": "", "gold": "use premium instead"}},
+		Rule{Key: "tags", DeprecatedValues: map[interface{}]string{"old": ""}},
+	}, OptionMapDeprecatedKeys(true), OptionDeprecationHook(hook), OptionParallel(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := validator.Validate(map[string]interface{}{
+		"zip":  "94107",
+		"fax":  "555-0100",
+		"plan": "legacy",
+		"tags": []string{"old"},
+		"name": "Ann",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedWarnings := []FieldError{
+		FieldError{Key: "zip", Message: "is deprecated, use postal_code instead, and will be removed on 2027-01-01"},
+		FieldError{Key: "fax", Message: "fax is no longer supported"},
+		FieldError{Key: "plan", Message: "legacy is deprecated"},
+	}
+	if !r.IsValid || !reflect.DeepEqual(r.FieldWarnings(), expectedWarnings) {
+		t.Errorf("Warnings should be %+v. They are %+v", expectedWarnings, r.FieldWarnings())
+	}
+
+	expectedValues := map[string]interface{}{"postal_code": "94107", "fax": "555-0100", "plan": "legacy", "tags": []string{"old"}, "name": "Ann"}
+	if !reflect.DeepEqual(r.Values, expectedValues) {
+		t.Errorf("Values should be %v. They are %v", expectedValues, r.Values)
+	}
+
+	expectedUsages := []DeprecationUsage{
+		DeprecationUsage{Key: "zip", Deprecation: zip},
+		DeprecationUsage{Key: "fax", Deprecation: validator.Rules()["fax"].Deprecation},
+		DeprecationUsage{Key: "plan", Value: "legacy"},
+	}
+	if !reflect.DeepEqual(usages, expectedUsages) {
+		t.Errorf("Usages should be %+v. They are %+v", expectedUsages, usages)
+	}
+
+	r, _ = validator.Validate(map[string]interface{}{"zip": "94107", "postal_code": "10001", "plan": "gold"})
+	if r.Values["postal_code"] != "10001" || r.Warnings["plan"][0] != "use premium instead" {
+		t.Errorf("Replacement should keep its value and gold should be deprecated. Response is %+v", r)
+	}
+
+	r, _ = validator.Validate(map[string]interface{}{"zip": "941"})
+	expectedErrors := []FieldError{FieldError{Key: "postal_code", Message: "Must have length 5"}}
+	if r.IsValid || !reflect.DeepEqual(r.FieldErrors(), expectedErrors) {
+		t.Errorf("Errors should be %+v. They are %+v", expectedErrors, r.FieldErrors())
+	}
+
+	r, _ = validator.Validate(map[string]interface{}{"zip": "941", "postal_code": "10001"})
+	if !r.IsValid {
+		t.Errorf("The replacement's own value should be validated. Errors are %+v", r.FieldErrors())
+	}
+
+	// the struct type is comparable, but the slice in its interface field isn't
+	r, err = validator.Validate(map[string]interface{}{"tags": struct{ Items interface{} }{Items: []string{"old"}}})
+	if err != nil || !r.IsValid || len(r.Warnings) != 0 {
+		t.Errorf("A value holding an uncomparable value shouldn't be deprecated. Response is %+v, error is %v", r, err)
+	}
+}
@@ -0,0 +1,143 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nmante/validator/funcs"
+)
+
+// Description describes a validator's rules and the constraints of their Funcs. It can be
+// serialized to JSON
+type Description struct {
+	Rules    []RuleDescription    `json:"rules"`
+	Patterns []PatternDescription `json:"patterns,omitempty"`
+}
+
+// RuleDescription describes a rule. Funcs that weren't described have an empty funcs.Meta
+type RuleDescription struct {
+	Key              string                  `json:"key"`
+	Description      string                  `json:"description,omitempty"`
+	Required         bool                    `json:"required"`
+	RequiredFor      map[string]bool         `json:"required_for,omitempty"`
+	Groups           []string                `json:"groups,omitempty"`
+	Aliases          []string                `json:"aliases,omitempty"`
+	Severity         string                  `json:"severity"`
+	Default          interface{}             `json:"default,omitempty"`
+	Examples         []interface{}           `json:"examples,omitempty"`
+	Deprecation      *DeprecationDescription `json:"deprecation,omitempty"`
+	DeprecatedValues []DeprecatedValue       `json:"deprecated_values,omitempty"`
+	Funcs            []funcs.Meta            `json:"funcs"`
+}
+
+// DeprecationDescription describes a Deprecation. Sunset is a date like "2027-01-01"
+type DeprecationDescription struct {
+	Replacement string `json:"replacement,omitempty"`
+	Sunset      string `json:"sunset,omitempty"`
+	Message     string `json:"message"`
+}
+
+// DeprecatedValue is a deprecated value of a key and its warning
+type DeprecatedValue struct {
+	Value   interface{} `json:"value"`
+	Message string      `json:"message"`
+}
+
+// PatternDescription describes a pattern rule. Pattern is the glob pattern, or the regular
+// expression if IsRegexp is set
+type PatternDescription struct {
+	Pattern    string       `json:"pattern"`
+	IsRegexp   bool         `json:"is_regexp,omitempty"`
+	MinMatches int          `json:"min_matches,omitempty"`
+	MaxMatches int          `json:"max_matches,omitempty"`
+	Severity   string       `json:"severity"`
+	Funcs      []funcs.Meta `json:"funcs"`
+}
+
+// Describe returns a description of the validator's rules, in registration order, and its pattern
+// rules. A validator nested in itself, directly or through other validators, is described by a
+// reference, since its description would be infinite
+func (v *Validator) Describe() Description {
+	return v.describe(nil)
+}
+
+// describe describes the validator inside the validators on path, outermost first
+func (v *Validator) describe(path []interface{}) Description {
+	path = append(append([]interface{}{}, path...), v)
+
+	d := Description{Rules: []RuleDescription{}}
+	for _, rule := range v.rules {
+		d.Rules = append(d.Rules, rule.describe(path))
+	}
+
+	for _, pr := range v.patternRules {
+		pd := PatternDescription{
+			Pattern:    pr.Pattern,
+			MinMatches: pr.MinMatches,
+			MaxMatches: pr.MaxMatches,
+			Severity:   pr.Severity.String(),
+			Funcs:      describeFuncs(pr.Funcs, path),
+		}
+
+		if pr.Regexp != nil {
+			pd.Pattern = pr.Regexp.String()
+			pd.IsRegexp = true
+		}
+
+		d.Patterns = append(d.Patterns, pd)
+	}
+
+	return d
+}
+
+func (r Rule) describe(path []interface{}) RuleDescription {
+	d := RuleDescription{
+		Key:         r.Key,
+		Description: r.Description,
+		Required:    r.IsRequired,
+		RequiredFor: r.RequiredFor,
+		Groups:      r.Groups,
+		Aliases:     r.Aliases,
+		Severity:    r.Severity.String(),
+		Default:     r.Default,
+		Examples:    r.Examples,
+		Funcs:       describeFuncs(r.Funcs, path),
+	}
+
+	if r.Deprecation != nil {
+		d.Deprecation = &DeprecationDescription{Replacement: r.Deprecation.Replacement, Message: r.Deprecation.warning()}
+		if !r.Deprecation.Sunset.IsZero() {
+			d.Deprecation.Sunset = r.Deprecation.Sunset.Format("2006-01-02")
+		}
+	}
+
+	for value, message := range r.DeprecatedValues {
+		d.DeprecatedValues = append(d.DeprecatedValues, DeprecatedValue{Value: value, Message: deprecatedValueWarning(value, message)})
+	}
+
+	sort.Slice(d.DeprecatedValues, func(i int, j int) bool {
+		return fmt.Sprint(d.DeprecatedValues[i].Value) < fmt.Sprint(d.DeprecatedValues[j].Value)
+	})
+
+	return d
+}
+
+func describeFuncs(fns []funcs.Func, path []interface{}) []funcs.Meta {
+	metas := make([]funcs.Meta, len(fns))
+	for i, f := range fns {
+		metas[i], _ = funcs.DescribePath(path, f)
+	}
+
+	return metas
+}
+
+// enclosing returns how many levels up v is on path, or 0 if it isn't on it
+func enclosing(path []interface{}, v *Validator) int {
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] == v {
+			return len(path) - i
+		}
+	}
+
+	return 0
+}
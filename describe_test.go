@@ -0,0 +1,98 @@
+package validator
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nmante/validator/funcs"
+)
+
+func TestDescribe(t *testing.T) {
+	address, _ := New([]Rule{Rule{Key: "zip", IsRequired: true, Funcs: []funcs.Func{funcs.IsLength(5)}}})
+
+	rules, err := BuildRules(
+		Key("qty").Required().Int().Between(1, 100).Description("Number of items").Default(1).Examples(2),
+		Key("email").String().Email().Message("must be a valid email"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules = append(rules,
+		Rule{Key: "address", Funcs: []funcs.Func{Nested(address)}},
+		Rule{
+			Key:              "plan",
+			Deprecation:      &Deprecation{Replacement: "tier", Sunset: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+			DeprecatedValues: map[interface{}]string{"gold": ""},
+			Severity:         funcs.SeverityWarning,
+		},
+	)
+
+	validator, _ := New(rules)
+	validator.AddPatternRule(PatternRule{Pattern: "meta_*", MaxMatches: 3, Funcs: []funcs.Func{funcs.IsLengthBetween(0, 64)}})
+
+	out, err := json.Marshal(validator.Describe())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"rules":[` +
+		`{"key":"qty","description":"Number of items","required":true,"severity":"error","default":1,"examples":[2],"funcs":[` +
+		`{"name":"IsInt","description":"must be a int","params":[{"name":"type","value":"int"}],"error_codes":["type"]},` +
+		`{"name":"IsBetween","description":"must be between 1 and 100","params":[{"name":"lower","value":1},{"name":"upper","value":100}],"error_codes":["between"]}]},` +
+		`{"key":"email","required":false,"severity":"error","funcs":[` +
+		`{"name":"IsType","description":"must be a string","params":[{"name":"type","value":"string"}],"error_codes":["type"]},` +
+		`{"name":"String.IsEmail","description":"must be a valid email","error_codes":["email"]}]},` +
+		`{"key":"address","required":false,"severity":"error","funcs":[` +
+		`{"name":"Nested","description":"must be an object","error_codes":["object"],"schema":{"rules":[` +
+		`{"key":"zip","required":true,"severity":"error","funcs":[{"name":"IsLength","description":"must have length 5","params":[{"name":"length","value":5}],"error_codes":["length"]}]}]}}]},` +
+		`{"key":"plan","required":false,"severity":"warning",` +
+		`"deprecation":{"replacement":"tier","sunset":"2027-01-01","message":"is deprecated, use tier instead, and will be removed on 2027-01-01"},` +
+		`"deprecated_values":[{"value":"gold","message":"gold is deprecated"}],"funcs":[]}],` +
+		`"patterns":[{"pattern":"meta_*","max_matches":3,"severity":"error","funcs":[` +
+		`{"name":"IsLengthBetween","description":"must have a length between 0 and 64","params":[{"name":"lower","value":0},{"name":"upper","value":64}],"error_codes":["length"]}]}]}`
+
+	if string(out) != expected {
+		t.Errorf("Description should be\n%s\nIt is\n%s", expected, out)
+	}
+}
+
+func TestDescribeRecursive(t *testing.T) {
+	category, _ := New([]Rule{Rule{Key: "name", IsRequired: true}})
+	category.AddRule("parent", Nested(category))
+
+	folder, _ := New([]Rule{})
+	file, _ := New([]Rule{Rule{Key: "folder", Funcs: []funcs.Func{Nested(folder)}}})
+	folder.AddRule("files", EachNested(file))
+
+	describeTests := []struct {
+		validator *Validator
+		expected  string
+	}{
+		{
+			validator: category,
+			expected: `{"rules":[{"key":"name","required":true,"severity":"error","funcs":[]},` +
+				`{"key":"parent","required":false,"severity":"error","funcs":[` +
+				`{"name":"Nested","description":"must be an object","params":[{"name":"enclosing","value":1}],"error_codes":["object"]}]}]}`,
+		},
+		{
+			validator: folder,
+			expected: `{"rules":[{"key":"files","required":false,"severity":"error","funcs":[` +
+				`{"name":"EachNested","children":[{"name":"Nested","description":"must be an object","error_codes":["object"],"schema":{"rules":[` +
+				`{"key":"folder","required":false,"severity":"error","funcs":[` +
+				`{"name":"Nested","description":"must be an object","params":[{"name":"enclosing","value":2}],"error_codes":["object"]}]}]}}]}]}]}`,
+		},
+	}
+
+	for i, test := range describeTests {
+		out, err := json.Marshal(test.validator.Describe())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(out) != test.expected {
+			t.Errorf("Test %d: description should be\n%s\nIt is\n%s", i, test.expected, out)
+		}
+	}
+}
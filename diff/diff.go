@@ -0,0 +1,617 @@
+// Package diff compares two versions of a validator and classifies the changes as breaking, when a
+// request the old version accepted could be rejected by the new one, or non-breaking. Validators
+// are compared by their descriptions, so rule files written by Validator.Describe can be compared
+// too. The report can be serialized to JSON for release gates.
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/nmante/validator"
+	"github.com/nmante/validator/funcs"
+)
+
+// Kinds of change
+const (
+	KeyAdded          = "key-added"
+	KeyRemoved        = "key-removed"
+	RequiredAdded     = "required-added"
+	RequiredRemoved   = "required-removed"
+	RangeNarrowed     = "range-narrowed"
+	RangeWidened      = "range-widened"
+	LengthNarrowed    = "length-narrowed"
+	LengthWidened     = "length-widened"
+	ValueRemoved      = "value-removed"
+	ValueAdded        = "value-added"
+	TypeChanged       = "type-changed"
+	ConstraintAdded   = "constraint-added"
+	ConstraintRemoved = "constraint-removed"
+	SeverityRaised    = "severity-raised"
+	SeverityLowered   = "severity-lowered"
+	AliasRemoved      = "alias-removed"
+	AliasAdded        = "alias-added"
+	Deprecated        = "deprecated"
+	PatternAdded      = "pattern-added"
+	PatternRemoved    = "pattern-removed"
+	NullRejected      = "null-rejected"
+	NullAllowed       = "null-allowed"
+)
+
+// Change is a difference between two versions of a key's rule. Key is like "address.zip" for nested
+// keys, or a pattern rule's pattern
+type Change struct {
+	Key      string `json:"key"`
+	Kind     string `json:"kind"`
+	Breaking bool   `json:"breaking"`
+	Message  string `json:"message"`
+}
+
+// Report is every change between two versions of a validator
+type Report struct {
+	Breaking bool     `json:"breaking"`
+	Changes  []Change `json:"changes"`
+}
+
+// Validators compares two versions of a validator
+func Validators(old *validator.Validator, new *validator.Validator) (Report, error) {
+	return Descriptions(old.Describe(), new.Describe())
+}
+
+// Descriptions compares the descriptions of two versions of a validator
+func Descriptions(old validator.Description, new validator.Description) (Report, error) {
+	// Descriptions are compared in their JSON form, so descriptions read from files and returned by
+	// Describe compare the same way
+	var err error
+	if old, err = normalize(old); err != nil {
+		return Report{}, err
+	}
+
+	if new, err = normalize(new); err != nil {
+		return Report{}, err
+	}
+
+	d := &differ{report: Report{Changes: []Change{}}}
+	d.description("", old, new)
+
+	return d.report, nil
+}
+
+// Read reads a validator description written as JSON, like by json.Marshal(v.Describe())
+func Read(r io.Reader) (validator.Description, error) {
+	d := validator.Description{}
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	err := decoder.Decode(&d)
+	return d, err
+}
+
+func normalize(d validator.Description) (validator.Description, error) {
+	out, err := json.Marshal(d)
+	if err != nil {
+		return validator.Description{}, err
+	}
+
+	return Read(bytes.NewReader(out))
+}
+
+type differ struct {
+	report Report
+}
+
+func (d *differ) add(key string, kind string, breaking bool, format string, args ...interface{}) {
+	d.report.Changes = append(d.report.Changes, Change{Key: key, Kind: kind, Breaking: breaking, Message: fmt.Sprintf(format, args...)})
+	d.report.Breaking = d.report.Breaking || breaking
+}
+
+func (d *differ) description(prefix string, old validator.Description, new validator.Description) {
+	oldRules := map[string]validator.RuleDescription{}
+	for _, rule := range old.Rules {
+		oldRules[rule.Key] = rule
+	}
+
+	newKeys := map[string]bool{}
+	for _, rule := range new.Rules {
+		newKeys[rule.Key] = true
+		key := prefix + rule.Key
+
+		oldRule, ok := oldRules[rule.Key]
+		if !ok {
+			if rule.Required {
+				d.add(key, KeyAdded, true, "required key added")
+			} else {
+				d.add(key, KeyAdded, false, "optional key added")
+			}
+			continue
+		}
+
+		d.rule(key, oldRule, rule)
+	}
+
+	for _, rule := range old.Rules {
+		if !newKeys[rule.Key] {
+			d.add(prefix+rule.Key, KeyRemoved, false, "key removed, so it's no longer validated")
+		}
+	}
+
+	oldPatterns := map[string]validator.PatternDescription{}
+	for _, p := range old.Patterns {
+		oldPatterns[p.Pattern] = p
+	}
+
+	newPatterns := map[string]bool{}
+	for _, p := range new.Patterns {
+		newPatterns[p.Pattern] = true
+		key := prefix + p.Pattern
+
+		oldPattern, ok := oldPatterns[p.Pattern]
+		if !ok {
+			d.add(key, PatternAdded, true, "pattern rule added")
+			continue
+		}
+
+		if p.MinMatches > oldPattern.MinMatches || p.MaxMatches > 0 && (oldPattern.MaxMatches == 0 || p.MaxMatches < oldPattern.MaxMatches) {
+			d.add(key, RangeNarrowed, true, "number of matching keys narrowed")
+		}
+
+		d.severity(key, "failures", oldPattern.Severity, p.Severity)
+		d.funcs(key, oldPattern.Funcs, p.Funcs)
+	}
+
+	for _, p := range old.Patterns {
+		if !newPatterns[p.Pattern] {
+			d.add(prefix+p.Pattern, PatternRemoved, false, "pattern rule removed")
+		}
+	}
+}
+
+func (d *differ) rule(key string, old validator.RuleDescription, new validator.RuleDescription) {
+	switch {
+	case new.Required && !old.Required:
+		d.add(key, RequiredAdded, true, "key became required")
+	case !new.Required && old.Required:
+		d.add(key, RequiredRemoved, false, "key became optional")
+	}
+
+	for _, scenario := range sortedKeys(new.RequiredFor) {
+		wasRequired, ok := old.RequiredFor[scenario]
+		if !ok {
+			wasRequired = old.Required
+		}
+
+		if new.RequiredFor[scenario] && !wasRequired {
+			d.add(key, RequiredAdded, true, "key became required for %s", scenario)
+		}
+	}
+
+	for _, alias := range old.Aliases {
+		if !contains(new.Aliases, alias) {
+			d.add(key, AliasRemoved, true, "alias %s removed", alias)
+		}
+	}
+
+	for _, alias := range new.Aliases {
+		if !contains(old.Aliases, alias) {
+			d.add(key, AliasAdded, false, "alias %s added", alias)
+		}
+	}
+
+	if new.Deprecation != nil && old.Deprecation == nil {
+		d.add(key, Deprecated, false, "key deprecated: %s", new.Deprecation.Message)
+	}
+
+	d.severity(key, "failures", old.Severity, new.Severity)
+	d.funcs(key, old.Funcs, new.Funcs)
+	d.nested(key, old.Funcs, new.Funcs)
+}
+
+// severity compares the severities that failures of a rule or Func, named by subject, are reported with
+func (d *differ) severity(key string, subject string, old string, new string) {
+	if old == new {
+		return
+	}
+
+	if new == funcs.SeverityError.String() {
+		d.add(key, SeverityRaised, true, "%s changed from %ss to errors", subject, old)
+	} else if old == funcs.SeverityError.String() {
+		d.add(key, SeverityLowered, false, "%s changed from errors to %ss", subject, new)
+	}
+}
+
+// funcs compares the constraints of two versions of a key's Funcs
+func (d *differ) funcs(key string, old []funcs.Meta, new []funcs.Meta) {
+	oldConstraints, newConstraints := constraintsOf(old), constraintsOf(new)
+
+	if oldConstraints.nullable && !newConstraints.nullable {
+		d.add(key, NullRejected, true, "null values no longer allowed")
+	} else if newConstraints.nullable && !oldConstraints.nullable {
+		d.add(key, NullAllowed, false, "null values allowed")
+	}
+
+	d.interval(key, oldConstraints.valueRange, newConstraints.valueRange, RangeNarrowed, RangeWidened, "range")
+	d.interval(key, oldConstraints.length, newConstraints.length, LengthNarrowed, LengthWidened, "length")
+
+	if oldConstraints._type != newConstraints._type && newConstraints._type != "" {
+		if oldConstraints._type == "" {
+			d.add(key, TypeChanged, true, "must now be a %s", newConstraints._type)
+		} else {
+			d.add(key, TypeChanged, true, "type changed from %s to %s", oldConstraints._type, newConstraints._type)
+		}
+	}
+
+	if newConstraints.values != nil {
+		if oldConstraints.values == nil {
+			d.add(key, ValueRemoved, true, "values limited to %s", strings.Join(newConstraints.values, ", "))
+		}
+
+		for _, value := range oldConstraints.values {
+			if !contains(newConstraints.values, value) {
+				d.add(key, ValueRemoved, true, "value %s removed", value)
+			}
+		}
+	}
+
+	if oldConstraints.values != nil {
+		for _, value := range newConstraints.values {
+			if !contains(oldConstraints.values, value) {
+				d.add(key, ValueAdded, false, "value %s added", value)
+			}
+		}
+	}
+
+	for _, variant := range oldConstraints.variants {
+		if !contains(newConstraints.variants, variant) {
+			d.add(key, ValueRemoved, true, "variant %s removed", variant)
+		}
+	}
+
+	for _, variant := range newConstraints.variants {
+		if !contains(oldConstraints.variants, variant) {
+			d.add(key, ValueAdded, false, "variant %s added", variant)
+		}
+	}
+
+	for _, label := range sortedKeys(newConstraints.severities) {
+		if old, ok := oldConstraints.severities[label]; ok {
+			d.severity(key, label+" failures", old, newConstraints.severities[label])
+		}
+	}
+
+	for _, label := range newConstraints.other {
+		if !contains(oldConstraints.other, label) {
+			d.add(key, ConstraintAdded, true, "%s added", label)
+		}
+	}
+
+	for _, label := range oldConstraints.other {
+		if !contains(newConstraints.other, label) {
+			d.add(key, ConstraintRemoved, false, "%s removed", label)
+		}
+	}
+}
+
+// interval compares two ranges of allowed values. A nil range allows every value
+func (d *differ) interval(key string, old *bounds, new *bounds, narrowed string, widened string, name string) {
+	if new == nil {
+		if old != nil {
+			d.add(key, widened, false, "%s %s removed", name, old)
+		}
+		return
+	}
+
+	if old == nil {
+		d.add(key, narrowed, true, "%s limited to %s", name, new)
+		return
+	}
+
+	tightened := new.lower > old.lower || new.upper < old.upper
+	loosened := new.lower < old.lower || new.upper > old.upper
+
+	switch {
+	case tightened && loosened:
+		// values on the tightened side are rejected now, so it's still breaking
+		d.add(key, narrowed, true, "both %s bounds changed, from %s to %s", name, old, new)
+	case tightened:
+		d.add(key, narrowed, true, "%s narrowed from %s to %s", name, old, new)
+	case loosened:
+		d.add(key, widened, false, "%s widened from %s to %s", name, old, new)
+	}
+}
+
+// nested compares the validators nested in two versions of a key's Funcs
+func (d *differ) nested(key string, old []funcs.Meta, new []funcs.Meta) {
+	oldSchemas, newSchemas := schemasOf(key, old), schemasOf(key, new)
+
+	for _, prefix := range sortedKeys(newSchemas) {
+		if oldSchema, ok := oldSchemas[prefix]; ok {
+			d.description(prefix, oldSchema, newSchemas[prefix])
+		}
+	}
+}
+
+// schemasOf returns the descriptions of validators nested in Funcs, by key prefix
+func schemasOf(key string, metas []funcs.Meta) map[string]validator.Description {
+	schemas := map[string]validator.Description{}
+	for _, meta := range metas {
+		switch meta.Name {
+		case "Nested":
+			if schema, ok := toDescription(meta.Schema); ok {
+				schemas[key+"."] = schema
+			}
+		case "NestedDiscriminator":
+			// each variant is compared with the same variant of the other version, like
+			// "payment[type=card].number"
+			discriminator, _ := meta.Param("key")
+			variants, _ := meta.Schema.(map[string]interface{})
+			for value, variant := range variants {
+				if schema, ok := toDescription(variant); ok {
+					schemas[fmt.Sprintf("%s[%v=%s].", key, discriminator, value)] = schema
+				}
+			}
+		}
+
+		prefix := key
+		if meta.Name == "EachNested" {
+			prefix += "[]"
+		}
+
+		for child, schema := range schemasOf(prefix, meta.Children) {
+			schemas[child] = schema
+		}
+	}
+
+	return schemas
+}
+
+// toDescription converts a decoded JSON schema to a description
+func toDescription(schema interface{}) (validator.Description, bool) {
+	out, err := json.Marshal(schema)
+	if err != nil {
+		return validator.Description{}, false
+	}
+
+	d, err := Read(bytes.NewReader(out))
+	return d, err == nil
+}
+
+// bounds is an inclusive range of numbers
+type bounds struct {
+	lower float64
+	upper float64
+}
+
+func (b *bounds) String() string {
+	format := func(f float64) string {
+		if math.IsInf(f, 0) {
+			return "any"
+		}
+
+		return fmt.Sprint(f)
+	}
+
+	if b.lower == b.upper {
+		return format(b.lower)
+	}
+
+	return format(b.lower) + " to " + format(b.upper)
+}
+
+// intersect narrows b to the values also in other
+func intersect(b *bounds, other bounds) *bounds {
+	if b == nil {
+		return &other
+	}
+
+	return &bounds{lower: math.Max(b.lower, other.lower), upper: math.Min(b.upper, other.upper)}
+}
+
+// constraints are the constraints of a key's Funcs that can be compared
+type constraints struct {
+	valueRange *bounds
+	length     *bounds
+	_type      string
+	// values are the allowed values, encoded as JSON, that every OneOf or SubsetOf allows. It's nil if
+	// every value is allowed
+	values []string
+	// variants are the NestedDiscriminator variants, like "type=card"
+	variants []string
+	// other are labels of the Funcs that aren't compared by what they allow
+	other []string
+	// nullable is true if nil values are allowed, because every Func that reports errors is Optional
+	nullable bool
+	// severities are the severities of the Funcs' failures, by label. Funcs that don't report errors
+	// only have a severity, since they can't reject a value
+	severities map[string]string
+}
+
+func constraintsOf(metas []funcs.Meta) constraints {
+	c := constraints{nullable: true, severities: map[string]string{}}
+	for _, meta := range metas {
+		severity := severityOf(meta, funcs.SeverityError.String())
+		if meta.Name != "Optional" && severity == funcs.SeverityError.String() {
+			c.nullable = false
+		}
+
+		c.add(meta, severity)
+	}
+
+	return c
+}
+
+// severityOf returns the severity a Func reports failures with, or the severity of the Func it's in
+func severityOf(meta funcs.Meta, enclosing string) string {
+	if severity, ok := meta.Param("severity"); ok {
+		return fmt.Sprint(severity)
+	}
+
+	return enclosing
+}
+
+func (c *constraints) add(meta funcs.Meta, severity string) {
+	severity = severityOf(meta, severity)
+	if meta.Name == "And" || meta.Name == "Optional" {
+		for _, child := range meta.Children {
+			c.add(child, severity)
+		}
+		return
+	}
+
+	if c.severities[label(meta)] != funcs.SeverityError.String() {
+		c.severities[label(meta)] = severity
+	}
+
+	if severity == funcs.SeverityError.String() {
+		// failures that aren't errors don't reject the value, so the Func doesn't constrain it
+		c.constrain(meta)
+	}
+}
+
+func (c *constraints) constrain(meta funcs.Meta) {
+	if lower, upper, ok := funcs.Bounds(meta); ok {
+		if b, ok := boundsOf(lower, upper); ok {
+			c.valueRange = intersect(c.valueRange, b)
+			return
+		}
+	} else if lower, upper, ok := funcs.LengthBounds(meta); ok {
+		if b, ok := boundsOf(lower, upper); ok {
+			c.length = intersect(c.length, b)
+			return
+		}
+	}
+
+	switch meta.Name {
+	case "Untyped", "Convert":
+		// the TypedFuncs' constraints are compared, then Untyped's type or Convert's target type
+		for _, child := range meta.Children {
+			c.constrain(child)
+		}
+	case "OneOf", "SubsetOf":
+		values, _ := meta.Param("values")
+		if list, ok := values.([]interface{}); ok {
+			allowed := []string{}
+			for _, value := range list {
+				if encoded := encode(value); c.values == nil || contains(c.values, encoded) {
+					allowed = append(allowed, encoded)
+				}
+			}
+
+			c.values = allowed
+			return
+		}
+	case "Nested", "EachNested":
+		// nested validators are compared key by key
+		return
+	case "NestedDiscriminator":
+		// the variants' validators are compared key by key
+		discriminator, _ := meta.Param("key")
+		variants, _ := meta.Schema.(map[string]interface{})
+		for value := range variants {
+			c.variants = append(c.variants, fmt.Sprintf("%v=%s", discriminator, value))
+		}
+
+		for _, p := range meta.Params {
+			if value := strings.TrimPrefix(p.Name, "enclosing."); value != p.Name {
+				c.variants = append(c.variants, fmt.Sprintf("%v=%s", discriminator, value))
+			}
+		}
+		sort.Strings(c.variants)
+		return
+	}
+
+	if _type, ok := funcs.CheckedType(meta); ok {
+		c._type = _type
+		return
+	}
+
+	c.other = append(c.other, label(meta))
+}
+
+// boundsOf converts the bounds from funcs.Bounds or funcs.LengthBounds to numbers. A nil bound
+// doesn't limit the range
+func boundsOf(lowerValue interface{}, upperValue interface{}) (bounds, bool) {
+	if lowerValue == nil {
+		lowerValue = math.Inf(-1)
+	}
+
+	if upperValue == nil {
+		upperValue = math.Inf(1)
+	}
+
+	lower, lowerOK := toFloat(lowerValue)
+	upper, upperOK := toFloat(upperValue)
+
+	return bounds{lower: lower, upper: upper}, lowerOK && upperOK
+}
+
+// label formats a Func like "IsUUID()" or "Contains(\"gift\")". Its severity isn't included
+func label(meta funcs.Meta) string {
+	values := []string{}
+	for _, p := range meta.Params {
+		if p.Name != "severity" {
+			values = append(values, encode(p.Value))
+		}
+	}
+
+	name := meta.Name
+	if name == "" {
+		name = "custom func"
+	}
+
+	return name + "(" + strings.Join(values, ", ") + ")"
+}
+
+func encode(v interface{}) string {
+	out, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(out)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	}
+
+	return 0, false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,189 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/nmante/validator"
+	"github.com/nmante/validator/funcs"
+)
+
+func TestValidators(t *testing.T) {
+	address := func(zip funcs.Func) *validator.Validator {
+		v, _ := validator.New([]validator.Rule{validator.Rule{Key: "zip", IsRequired: true, Funcs: []funcs.Func{zip}}})
+		return v
+	}
+
+	variant := func(key string, f funcs.Func) *validator.Validator {
+		v, _ := validator.New([]validator.Rule{validator.Rule{Key: key, IsRequired: true, Funcs: []funcs.Func{f}}})
+		return v
+	}
+
+	old, _ := validator.New([]validator.Rule{
+		validator.Key("qty").Int().Between(1, 100).Required().MustBuild(),
+		validator.Key("price").Float64().Between(0.0, 10.0).MustBuild(),
+		validator.Rule{Key: "status", Funcs: []funcs.Func{funcs.Untyped(funcs.OneOf("new", "paid", "shipped"))}},
+		validator.Key("note").String().LengthBetween(0, 100).MustBuild(),
+		validator.Key("coupon").MustBuild(),
+		validator.Rule{Key: "address", Funcs: []funcs.Func{validator.Nested(address(funcs.IsLength(5)))}},
+		validator.Rule{Key: "page", Funcs: []funcs.Func{funcs.Untyped(funcs.Between(1, 100))}},
+		validator.Rule{Key: "limit", Funcs: []funcs.Func{funcs.Untyped(funcs.Convert(strconv.Atoi, funcs.Min(1)))}},
+		validator.Rule{Key: "rating", Funcs: []funcs.Func{funcs.Optional(funcs.Untyped(funcs.Between(1, 5)))}},
+		validator.Rule{Key: "score", Funcs: []funcs.Func{funcs.Warning(funcs.Untyped(funcs.Between(0, 10)))}},
+		validator.Rule{Key: "tier", Funcs: []funcs.Func{funcs.Untyped(funcs.OneOf("basic", "premium"))}},
+		validator.Rule{Key: "payment", Funcs: []funcs.Func{validator.NestedDiscriminator("type", map[string]*validator.Validator{
+			"card": variant("number", funcs.IsLengthBetween(12, 19)),
+			"bank": variant("iban", funcs.IsLengthBetween(15, 34)),
+		})}},
+	})
+
+	new, _ := validator.New([]validator.Rule{
+		validator.Key("qty").Int().Between(1, 50).Required().MustBuild(),
+		validator.Key("price").Float64().Between(0.0, 20.0).MustBuild(),
+		validator.Rule{Key: "status", Funcs: []funcs.Func{funcs.Untyped(funcs.OneOf("new", "paid", "cancelled"))}},
+		validator.Key("note").String().LengthBetween(0, 200).Required().MustBuild(),
+		validator.Key("gift").MustBuild(),
+		validator.Key("email").String().Email().Required().MustBuild(),
+		validator.Rule{Key: "address", Funcs: []funcs.Func{validator.Nested(address(funcs.IsLengthBetween(5, 10)))}},
+		validator.Rule{Key: "page", Funcs: []funcs.Func{funcs.Untyped(funcs.Between(1, 10))}},
+		validator.Rule{Key: "limit", Funcs: []funcs.Func{funcs.Untyped(funcs.Convert(strconv.Atoi, funcs.Between(1, 50)))}},
+		validator.Rule{Key: "rating", Funcs: []funcs.Func{funcs.Untyped(funcs.Between(1, 5))}},
+		validator.Rule{Key: "score", Funcs: []funcs.Func{funcs.Untyped(funcs.Between(0, 10))}},
+		validator.Rule{Key: "tier", Funcs: []funcs.Func{funcs.Optional(funcs.WithSeverity(funcs.SeverityInfo, funcs.Untyped(funcs.OneOf("basic", "premium"))))}},
+		validator.Rule{Key: "payment", Funcs: []funcs.Func{validator.NestedDiscriminator("type", map[string]*validator.Validator{
+			"card":   variant("number", funcs.IsLength(16)),
+			"wallet": variant("wallet_id", funcs.IsLengthBetween(1, 64)),
+		})}},
+	})
+
+	report, err := Validators(old, new)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Change{
+		Change{Key: "qty", Kind: RangeNarrowed, Breaking: true, Message: "range narrowed from 1 to 100 to 1 to 50"},
+		Change{Key: "price", Kind: RangeWidened, Breaking: false, Message: "range widened from 0 to 10 to 0 to 20"},
+		Change{Key: "status", Kind: ValueRemoved, Breaking: true, Message: `value "shipped" removed`},
+		Change{Key: "status", Kind: ValueAdded, Breaking: false, Message: `value "cancelled" added`},
+		Change{Key: "note", Kind: RequiredAdded, Breaking: true, Message: "key became required"},
+		Change{Key: "note", Kind: LengthWidened, Breaking: false, Message: "length widened from 0 to 100 to 0 to 200"},
+		Change{Key: "gift", Kind: KeyAdded, Breaking: false, Message: "optional key added"},
+		Change{Key: "email", Kind: KeyAdded, Breaking: true, Message: "required key added"},
+		Change{Key: "address.zip", Kind: LengthWidened, Breaking: false, Message: "length widened from 5 to 5 to 10"},
+		Change{Key: "page", Kind: RangeNarrowed, Breaking: true, Message: "range narrowed from 1 to 100 to 1 to 10"},
+		Change{Key: "limit", Kind: RangeNarrowed, Breaking: true, Message: "range narrowed from 1 to any to 1 to 50"},
+		Change{Key: "rating", Kind: NullRejected, Breaking: true, Message: "null values no longer allowed"},
+		Change{Key: "score", Kind: NullRejected, Breaking: true, Message: "null values no longer allowed"},
+		Change{Key: "score", Kind: RangeNarrowed, Breaking: true, Message: "range limited to 0 to 10"},
+		Change{Key: "score", Kind: TypeChanged, Breaking: true, Message: "must now be a int"},
+		Change{Key: "score", Kind: SeverityRaised, Breaking: true, Message: "Untyped(\"int\") failures changed from warnings to errors"},
+		Change{Key: "tier", Kind: NullAllowed, Breaking: false, Message: "null values allowed"},
+		Change{Key: "tier", Kind: SeverityLowered, Breaking: false, Message: "Untyped(\"string\") failures changed from errors to infos"},
+		Change{Key: "payment", Kind: ValueRemoved, Breaking: true, Message: "variant type=bank removed"},
+		Change{Key: "payment", Kind: ValueAdded, Breaking: false, Message: "variant type=wallet added"},
+		Change{Key: "payment[type=card].number", Kind: LengthNarrowed, Breaking: true, Message: "length narrowed from 12 to 19 to 16"},
+		Change{Key: "coupon", Kind: KeyRemoved, Breaking: false, Message: "key removed, so it's no longer validated"},
+	}
+
+	if !report.Breaking || !reflect.DeepEqual(report.Changes, expected) {
+		t.Errorf("Changes should be\n%+v\nThey are\n%+v", expected, report.Changes)
+	}
+}
+
+func TestDescriptions(t *testing.T) {
+	old, _ := validator.New([]validator.Rule{
+		validator.Key("qty").Int().MustBuild(),
+		validator.Key("sku").Warning().Func(funcs.Contains("-")).MustBuild(),
+		validator.Rule{Key: "zip", Aliases: []string{"postal_code"}},
+	})
+
+	new, _ := validator.New([]validator.Rule{
+		validator.Key("qty").String().Between("1", "9").MustBuild(),
+		validator.Key("sku").Func(funcs.Contains("-")).Func(funcs.IsLength(8)).MustBuild(),
+		validator.Rule{Key: "zip", Deprecation: &validator.Deprecation{Replacement: "postcode"}},
+	})
+
+	// the old version is read back from a file, like a release gate would
+	out, _ := json.Marshal(old.Describe())
+	oldDescription, err := Read(bytes.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Descriptions(oldDescription, new.Describe())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kinds := []string{}
+	for _, change := range report.Changes {
+		kinds = append(kinds, change.Key+" "+change.Kind)
+	}
+
+	expected := []string{
+		"qty type-changed",
+		"qty constraint-added",
+		"sku severity-raised",
+		"sku length-narrowed",
+		"zip alias-removed",
+		"zip deprecated",
+	}
+
+	if !report.Breaking || !reflect.DeepEqual(kinds, expected) {
+		t.Errorf("Changes should be %v. They are %+v", expected, report.Changes)
+	}
+
+	if report, _ := Validators(new, new); report.Breaking || len(report.Changes) != 0 {
+		t.Errorf("A validator shouldn't differ from itself. Changes are %+v", report.Changes)
+	}
+}
+
+func TestRecursive(t *testing.T) {
+	category := func(name funcs.Func) *validator.Validator {
+		v, _ := validator.New([]validator.Rule{validator.Rule{Key: "name", IsRequired: true, Funcs: []funcs.Func{name}}})
+		return v.AddRule("parent", validator.Nested(v))
+	}
+
+	report, err := Validators(category(funcs.IsLengthBetween(1, 50)), category(funcs.IsLengthBetween(1, 20)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Change{Change{Key: "name", Kind: LengthNarrowed, Breaking: true, Message: "length narrowed from 1 to 50 to 1 to 20"}}
+	if !reflect.DeepEqual(report.Changes, expected) {
+		t.Errorf("Changes should be\n%+v\nThey are\n%+v", expected, report.Changes)
+	}
+}
+
+func TestCombinedConstraints(t *testing.T) {
+	old, _ := validator.New([]validator.Rule{
+		validator.Rule{Key: "size", Funcs: []funcs.Func{funcs.Untyped(funcs.OneOf("s", "m", "l"))}},
+		validator.Rule{Key: "fit", Funcs: []funcs.Func{funcs.Untyped(funcs.OneOf("slim", "regular", "loose"))}},
+		validator.Rule{Key: "qty", Funcs: []funcs.Func{funcs.Untyped(funcs.Between(1, 10))}},
+	})
+
+	new, _ := validator.New([]validator.Rule{
+		validator.Rule{Key: "size", Funcs: []funcs.Func{funcs.Untyped(funcs.OneOf("s", "m", "l", "xl")), funcs.Untyped(funcs.OneOf("m", "l", "xl"))}},
+		validator.Rule{Key: "fit", Funcs: []funcs.Func{funcs.Untyped(funcs.OneOf("slim", "regular", "loose", "relaxed")), funcs.Untyped(funcs.OneOf("loose", "slim", "regular", "relaxed"))}},
+		validator.Rule{Key: "qty", Funcs: []funcs.Func{funcs.Untyped(funcs.Between(5, 20))}},
+	})
+
+	report, err := Validators(old, new)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Change{
+		Change{Key: "size", Kind: ValueRemoved, Breaking: true, Message: `value "s" removed`},
+		Change{Key: "size", Kind: ValueAdded, Breaking: false, Message: `value "xl" added`},
+		Change{Key: "fit", Kind: ValueAdded, Breaking: false, Message: `value "relaxed" added`},
+		Change{Key: "qty", Kind: RangeNarrowed, Breaking: true, Message: "both range bounds changed, from 1 to 10 to 5 to 20"},
+	}
+	if !report.Breaking || !reflect.DeepEqual(report.Changes, expected) {
+		t.Errorf("Changes should be\n%+v\nThey are\n%+v", expected, report.Changes)
+	}
+}
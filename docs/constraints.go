@@ -0,0 +1,303 @@
+package docs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/nmante/validator"
+	"github.com/nmante/validator/funcs"
+)
+
+// summary is the plain English description of a list of Funcs. hasLength is set by length checks,
+// which imply a string or an array when nothing else sets the type
+type summary struct {
+	_type     string
+	ref       string
+	phrases   []string
+	nested    []nestedSchema
+	hasLength bool
+}
+
+// nestedSchema is a validator nested in a key's value. condition is a format string for the key,
+// for the variants of a discriminated union
+type nestedSchema struct {
+	prefix      string
+	condition   string
+	description validator.Description
+}
+
+// summarize describes Funcs that all check one value
+func summarize(metas []funcs.Meta) summary {
+	s := summary{}
+	for _, meta := range metas {
+		s.add(meta)
+	}
+
+	return s
+}
+
+// rowType returns the type for a row's Type column. Length checks without a type imply a string or
+// an array
+func (s summary) rowType() string {
+	if s._type == "" && s.hasLength {
+		return "string or array"
+	}
+
+	return s._type
+}
+
+// constraints returns the summary's phrases without its type, for a row whose type has its own
+// column, like "between 1 and 100"
+func (s summary) constraints() string {
+	return strings.Join(s.phrases, ", ")
+}
+
+// text returns the summary as a phrase, like "integer between 1 and 100"
+func (s summary) text() string {
+	parts := []string{}
+	for _, part := range []string{s._type, s.ref, strings.Join(s.phrases, ", ")} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (s *summary) setType(_type string) {
+	if s._type == "" {
+		s._type = _type
+	}
+}
+
+func (s *summary) phrase(format string, args ...interface{}) {
+	s.phrases = append(s.phrases, fmt.Sprintf(format, args...))
+}
+
+func (s *summary) add(meta funcs.Meta) {
+	if lower, upper, ok := funcs.Bounds(meta); ok {
+		switch {
+		case upper == nil:
+			s.phrase("at least %v", lower)
+		case lower == nil:
+			s.phrase("at most %v", upper)
+		case reflect.DeepEqual(lower, upper):
+			s.phrase("equal to %v", lower)
+		default:
+			s.phrase("between %v and %v", lower, upper)
+		}
+		return
+	}
+
+	if lower, upper, ok := funcs.LengthBounds(meta); ok {
+		s.hasLength = true
+		if reflect.DeepEqual(lower, upper) {
+			s.phrase("with length %v", lower)
+		} else {
+			s.phrase("with length between %v and %v", lower, upper)
+		}
+		return
+	}
+
+	switch meta.Name {
+	case "OneOf":
+		values, _ := meta.Param("values")
+		s.phrase("one of %s", encodeList(values))
+	case "String.IsEmail":
+		s.setType("string")
+		s.phrase("that is an email address")
+	case "Unique", "UniqueBy":
+		s.setType("array")
+		s.phrase("with unique elements")
+	case "Contains":
+		value, _ := meta.Param("value")
+		s.setType("array")
+		s.phrase("containing %s", encode(value))
+	case "SubsetOf":
+		values, _ := meta.Param("values")
+		s.setType("array")
+		s.phrase("with elements from %s", encodeList(values))
+	case "Sorted":
+		s.setType("array")
+		s.phrase("sorted in ascending order")
+	case "Each", "EachValue":
+		s.setType("array")
+		if element := summarize(meta.Children); element.text() != "" {
+			s.phrase("each element %s", element.text())
+		}
+	case "EachKey":
+		s.setType("object")
+		if key := summarize(meta.Children); key.text() != "" {
+			s.phrase("each key %s", key.text())
+		}
+	case "Nested":
+		s.setType("object")
+		if d, ok := meta.Schema.(validator.Description); ok {
+			s.nested = append(s.nested, nestedSchema{prefix: ".", description: d})
+		}
+
+		if levels, ok := meta.Param("enclosing"); ok {
+			s.phrase("validated like %s", enclosingObject(levels))
+		}
+	case "EachNested":
+		s.setType("array of objects")
+		for _, child := range meta.Children {
+			if d, ok := child.Schema.(validator.Description); ok {
+				s.nested = append(s.nested, nestedSchema{prefix: "[].", description: d})
+			}
+		}
+	case "NestedDiscriminator":
+		s.setType("object")
+		key, _ := meta.Param("key")
+		variants, _ := meta.Schema.(map[string]validator.Description)
+
+		values := []string{}
+		for value := range variants {
+			values = append(values, value)
+		}
+
+		enclosing := []string{}
+		for _, p := range meta.Params {
+			if value := strings.TrimPrefix(p.Name, "enclosing."); value != p.Name {
+				values = append(values, value)
+				enclosing = append(enclosing, fmt.Sprintf("%s is validated like %s", value, enclosingObject(p.Value)))
+			}
+		}
+		sort.Strings(values)
+
+		s.phrase("with a variant for each %v: %s", key, strings.Join(values, ", "))
+		s.phrases = append(s.phrases, enclosing...)
+		for _, value := range values {
+			if d, ok := variants[value]; ok {
+				condition := fmt.Sprintf("When %%s.%v is %q", key, value)
+				s.nested = append(s.nested, nestedSchema{prefix: ".", condition: condition, description: d})
+			}
+		}
+	case "Untyped":
+		_type, _ := meta.Param("type")
+		s.setType(typeName(fmt.Sprint(_type)))
+		for _, child := range meta.Children {
+			s.add(child)
+		}
+	case "Convert":
+		to, _ := meta.Param("to")
+		converted := article(typeName(fmt.Sprint(to)))
+		if text := summarize(meta.Children).text(); text != "" {
+			converted += " " + text
+		}
+
+		s.phrase("convertible to %s", converted)
+	case "Ref":
+		name, _ := meta.Param("name")
+		s.ref = fmt.Sprint(name)
+	case "EachRef":
+		s.setType("array of")
+		for _, child := range meta.Children {
+			if name, ok := child.Param("name"); ok {
+				s.ref = fmt.Sprint(name)
+			}
+		}
+	case "And", "Optional":
+		for _, child := range meta.Children {
+			s.add(child)
+		}
+
+		if meta.Name == "Optional" {
+			s.phrase("or empty")
+		}
+	case "AnyOf", "ExactlyOne", "When":
+		alternatives := []string{}
+		for _, child := range meta.Children {
+			alternatives = append(alternatives, summarize([]funcs.Meta{child}).text())
+		}
+
+		switch meta.Name {
+		case "AnyOf":
+			s.phrase("either %s", strings.Join(alternatives, " or "))
+		case "ExactlyOne":
+			s.phrase("exactly one of: %s", strings.Join(alternatives, "; "))
+		default:
+			s.phrase("depending on the value, %s", strings.Join(alternatives, " or "))
+		}
+	case "Not":
+		s.phrase("not %s", summarize(meta.Children).text())
+	case "Expr":
+		expression, _ := meta.Param("expression")
+		s.phrase("satisfying `%v`", expression)
+	default:
+		if _type, ok := funcs.CheckedType(meta); ok {
+			s.addType(meta, _type)
+			return
+		}
+
+		switch {
+		case meta.Description != "":
+			s.phrase("that %s", strings.TrimPrefix(meta.Description, "that "))
+		case meta.Name != "":
+			s.phrase("passing %s", meta.Name)
+		default:
+			s.phrase("passing a custom check")
+		}
+	}
+}
+
+// enclosingObject names the object a recursive validator is nested in, levels up from the value
+func enclosingObject(levels interface{}) string {
+	if fmt.Sprint(levels) == "1" {
+		return "the enclosing object"
+	}
+
+	return fmt.Sprintf("the object %v levels up", levels)
+}
+
+// addType adds a type check. Checks on strings that hold another type, like String.IsInt, make the
+// value a string
+func (s *summary) addType(meta funcs.Meta, _type string) {
+	if meta.Name == "IsTransformableTo" || strings.HasPrefix(meta.Name, "String.") {
+		s.setType("string")
+		s.phrase("containing %s", article(typeName(_type)))
+		return
+	}
+
+	s.setType(typeName(_type))
+}
+
+// typeName returns the JSON type for a Go type name
+func typeName(goType string) string {
+	switch {
+	case strings.HasPrefix(goType, "int"), strings.HasPrefix(goType, "uint"):
+		return "integer"
+	case strings.HasPrefix(goType, "float"):
+		return "number"
+	case strings.HasPrefix(goType, "complex"):
+		return "complex number"
+	case goType == "bool":
+		return "boolean"
+	case strings.HasPrefix(goType, "[]"):
+		return "array"
+	case strings.HasPrefix(goType, "map["):
+		return "object"
+	}
+
+	return goType
+}
+
+func article(noun string) string {
+	if noun != "" && strings.ContainsRune("aeiou", rune(noun[0])) {
+		return "an " + noun
+	}
+
+	return "a " + noun
+}
+
+// encodeList formats a slice of values as JSON values separated by commas
+func encodeList(v interface{}) string {
+	values, ok := v.([]interface{})
+	if !ok {
+		return encode(v)
+	}
+
+	return encodeAll(values)
+}
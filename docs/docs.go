@@ -0,0 +1,218 @@
+// Package docs renders validators as API reference documentation, in Markdown or as a standalone
+// HTML page. Each key's type and constraints are written in plain English, like "integer between 1
+// and 100", from the funcs.Meta of its rule's Funcs. Funcs that aren't described by the built in
+// constructors are documented by their Meta's description or name.
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nmante/validator"
+	"github.com/nmante/validator/funcs"
+)
+
+// Document is a set of validators to document, each in its own section
+type Document struct {
+	Title    string
+	Sections []Section
+}
+
+// Section documents a validator. Keys of nested validators have their own rows, like
+// "address.city" or "items[].sku"
+type Section struct {
+	Name     string
+	Rows     []Row
+	Patterns []PatternRow
+}
+
+// Row documents a key. Ref is the name of the section that documents the key's value, for values
+// validated with Registry.Ref. Type then comes before the reference, like "array of"
+type Row struct {
+	Key         string
+	Type        string
+	Ref         string
+	Required    string
+	Constraints string
+	Default     string
+	Examples    string
+	Notes       string
+}
+
+// PatternRow documents a pattern rule
+type PatternRow struct {
+	Pattern     string
+	Matches     string
+	Constraints string
+}
+
+// ForValidator returns a document with a section for a validator
+func ForValidator(title string, v *validator.Validator) Document {
+	return Document{Title: title, Sections: []Section{NewSection("", v.Describe())}}
+}
+
+// ForRegistry returns a document with a section for each of a registry's validators, by name
+func ForRegistry(title string, r *validator.Registry) Document {
+	d := Document{Title: title}
+	for _, name := range r.Names() {
+		v, _ := r.Get(name)
+		d.Sections = append(d.Sections, NewSection(name, v.Describe()))
+	}
+
+	return d
+}
+
+// NewSection returns a section documenting a validator's description
+func NewSection(name string, d validator.Description) Section {
+	s := Section{Name: name}
+	s.addRules("", "", d)
+
+	for _, p := range d.Patterns {
+		matches := "any number"
+		switch {
+		case p.MinMatches > 0 && p.MaxMatches > 0:
+			matches = fmt.Sprintf("%d to %d", p.MinMatches, p.MaxMatches)
+		case p.MinMatches > 0:
+			matches = fmt.Sprintf("at least %d", p.MinMatches)
+		case p.MaxMatches > 0:
+			matches = fmt.Sprintf("at most %d", p.MaxMatches)
+		}
+
+		pattern := p.Pattern
+		if p.IsRegexp {
+			pattern = "/" + pattern + "/"
+		}
+
+		s.Patterns = append(s.Patterns, PatternRow{Pattern: pattern, Matches: matches, Constraints: summarize(p.Funcs).text()})
+	}
+
+	return s
+}
+
+// addRules adds rows for a description's rules, with keys prefixed by prefix. condition notes when
+// the rules apply, for the variants of a discriminated union
+func (s *Section) addRules(prefix string, condition string, d validator.Description) {
+	for _, rule := range d.Rules {
+		key := prefix + rule.Key
+		sum := summarize(rule.Funcs)
+
+		row := Row{
+			Key:         key,
+			Type:        sum.rowType(),
+			Ref:         sum.ref,
+			Required:    required(rule),
+			Constraints: sum.constraints(),
+			Default:     encode(rule.Default),
+			Examples:    encodeAll(rule.Examples),
+			Notes:       notes(rule, condition),
+		}
+		s.Rows = append(s.Rows, row)
+
+		for _, nested := range sum.nested {
+			nestedCondition := condition
+			if nested.condition != "" {
+				nestedCondition = joinNotes(condition, fmt.Sprintf(nested.condition, key))
+			}
+
+			s.addRules(key+nested.prefix, nestedCondition, nested.description)
+		}
+	}
+}
+
+func required(rule validator.RuleDescription) string {
+	r := "no"
+	if rule.Required {
+		r = "yes"
+	}
+
+	scenarios := []string{}
+	for scenario, isRequired := range rule.RequiredFor {
+		if isRequired != rule.Required {
+			scenarios = append(scenarios, scenario)
+		}
+	}
+
+	if len(scenarios) == 0 {
+		return r
+	}
+
+	sort.Strings(scenarios)
+	if rule.Required {
+		return "yes, except for " + strings.Join(scenarios, ", ")
+	}
+
+	return "for " + strings.Join(scenarios, ", ")
+}
+
+func notes(rule validator.RuleDescription, condition string) string {
+	n := condition
+
+	if rule.Description != "" {
+		n = joinNotes(n, rule.Description)
+	}
+
+	if len(rule.Aliases) > 0 {
+		n = joinNotes(n, "Also accepted as "+strings.Join(rule.Aliases, ", "))
+	}
+
+	if len(rule.Groups) > 0 {
+		n = joinNotes(n, "Only for "+strings.Join(rule.Groups, ", "))
+	}
+
+	if rule.Severity != funcs.SeverityError.String() {
+		n = joinNotes(n, "Failures are "+rule.Severity+"s only")
+	}
+
+	if rule.Deprecation != nil {
+		message := rule.Deprecation.Message
+		if strings.HasPrefix(message, "is deprecated") {
+			n = joinNotes(n, "Deprecated"+strings.TrimPrefix(message, "is deprecated"))
+		} else {
+			n = joinNotes(n, "Deprecated: "+message)
+		}
+	}
+
+	if len(rule.DeprecatedValues) > 0 {
+		values := []string{}
+		for _, dv := range rule.DeprecatedValues {
+			values = append(values, encode(dv.Value))
+		}
+
+		n = joinNotes(n, "Deprecated values: "+strings.Join(values, ", "))
+	}
+
+	return n
+}
+
+func joinNotes(a string, b string) string {
+	if a == "" {
+		return b
+	}
+
+	return a + ". " + b
+}
+
+// encode formats a value as JSON, the way clients send it
+func encode(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(out)
+}
+
+func encodeAll(values []interface{}) string {
+	encoded := make([]string, len(values))
+	for i, v := range values {
+		encoded[i] = encode(v)
+	}
+
+	return strings.Join(encoded, ", ")
+}
@@ -0,0 +1,137 @@
+package docs
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nmante/validator"
+	"github.com/nmante/validator/compare"
+	"github.com/nmante/validator/funcs"
+	"github.com/nmante/validator/transform"
+	"github.com/nmante/validator/types"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func orderDocument(t *testing.T) Document {
+	address, _ := validator.New([]validator.Rule{
+		validator.Rule{Key: "zip", IsRequired: true, Funcs: []funcs.Func{funcs.String.IsInt, funcs.IsLength(5)}},
+	})
+
+	card, _ := validator.New([]validator.Rule{validator.Rule{Key: "number", IsRequired: true, Funcs: []funcs.Func{funcs.IsLengthBetween(12, 19)}}})
+	invoice, _ := validator.New([]validator.Rule{validator.Rule{Key: "due_days", Funcs: []funcs.Func{funcs.IsInt}}})
+
+	line, _ := validator.New([]validator.Rule{
+		validator.Rule{Key: "sku", IsRequired: true, Funcs: []funcs.Func{funcs.IsType(types.String)}},
+	})
+
+	rules, err := validator.BuildRules(
+		validator.Key("qty").Required().Int().Between(1, 100).Description("Number of items").Default(1).Examples(2, 10),
+		validator.Key("email").String().Email(),
+		validator.Key("coupon").String().AsInt().Warning(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules = append(rules,
+		validator.Rule{Key: "shipping_address", Funcs: []funcs.Func{validator.Nested(address)}, RequiredFor: map[string]bool{"create": true}},
+		validator.Rule{Key: "items", Funcs: []funcs.Func{validator.EachNested(line), funcs.Unique}},
+		validator.Rule{Key: "payment", Funcs: []funcs.Func{validator.NestedDiscriminator("type", map[string]*validator.Validator{"card": card, "invoice": invoice})}},
+		validator.Rule{Key: "tags", Funcs: []funcs.Func{funcs.SubsetOf("gift", "fragile"), funcs.Each(funcs.IsLengthBetween(1, 10))}},
+		validator.Rule{Key: "priority", Funcs: []funcs.Func{funcs.Optional(funcs.IsBetween(transform.None, compare.Int, 1, 5))}},
+		validator.Rule{
+			Key:              "plan",
+			Aliases:          []string{"tier"},
+			Deprecation:      &validator.Deprecation{Replacement: "subscription", Sunset: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+			DeprecatedValues: map[interface{}]string{"gold": ""},
+		},
+		validator.Rule{Key: "note", Funcs: []funcs.Func{funcs.WithMeta(funcs.Meta{Name: "IsPolite", Description: "must be polite"}, funcs.IsBool)}},
+		validator.Rule{Key: "page_size", Funcs: []funcs.Func{funcs.Untyped(funcs.Convert(strconv.Atoi, funcs.Between(1, 100)))}},
+		validator.Rule{Key: "status", Funcs: []funcs.Func{funcs.Untyped(funcs.OneOf("draft", "submitted"))}},
+		validator.Rule{Key: "discount", Funcs: []funcs.Func{funcs.Untyped(funcs.Min(0.0)), funcs.Untyped(funcs.Max(0.5))}},
+		validator.Rule{Key: "labels", Funcs: []funcs.Func{funcs.IsType(reflect.TypeOf([]string{})), funcs.IsLengthBetween(0, 5)}},
+		validator.Rule{Key: "sizes", Funcs: []funcs.Func{funcs.IsType(reflect.TypeOf(map[string]int{}))}},
+	)
+
+	v, _ := validator.New(rules)
+	v.AddPatternRule(validator.PatternRule{Pattern: "meta_*", MaxMatches: 3, Funcs: []funcs.Func{funcs.IsLengthBetween(0, 64)}})
+
+	return ForValidator("Orders API", v)
+}
+
+func registryDocument(t *testing.T) Document {
+	registry, _ := validator.NewRegistry()
+
+	comment, _ := validator.New([]validator.Rule{
+		validator.Rule{Key: "body", IsRequired: true, Funcs: []funcs.Func{funcs.IsLengthBetween(1, 500)}},
+		validator.Rule{Key: "replies", Funcs: []funcs.Func{registry.EachRef("comment")}},
+		validator.Rule{Key: "author", Funcs: []funcs.Func{registry.Ref("user")}},
+	})
+	user, _ := validator.New([]validator.Rule{validator.Rule{Key: "name", IsRequired: true}})
+
+	registry.Define("comment", comment).Define("user", user)
+
+	return ForRegistry("Comments API", registry)
+}
+
+func TestGolden(t *testing.T) {
+	goldenTests := []struct {
+		document Document
+		html     bool
+		golden   string
+	}{
+		{document: orderDocument(t), golden: "testdata/order.md"},
+		{document: orderDocument(t), html: true, golden: "testdata/order.html"},
+		{document: registryDocument(t), golden: "testdata/registry.md"},
+		{document: registryDocument(t), html: true, golden: "testdata/registry.html"},
+	}
+
+	for _, test := range goldenTests {
+		out := &bytes.Buffer{}
+
+		render := test.document.Markdown
+		if test.html {
+			render = test.document.HTML
+		}
+
+		if err := render(out); err != nil {
+			t.Fatal(err)
+		}
+
+		if *update {
+			if err := os.WriteFile(test.golden, out.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		golden, err := os.ReadFile(test.golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(out.Bytes(), golden) {
+			t.Errorf("Output doesn't match %s. Run go test -update to update it.\n%s", test.golden, out)
+		}
+	}
+}
+
+func TestRecursive(t *testing.T) {
+	category, _ := validator.New([]validator.Rule{validator.Rule{Key: "name", IsRequired: true}})
+	category.AddRule("parent", validator.Nested(category))
+
+	out := &bytes.Buffer{}
+	if err := ForValidator("Categories", category).Markdown(out); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(out.Bytes(), []byte("| object | no | validated like the enclosing object |")) {
+		t.Errorf("parent should be documented as recursive.\n%s", out)
+	}
+}
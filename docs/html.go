@@ -0,0 +1,55 @@
+package docs
+
+import (
+	"html/template"
+	"io"
+)
+
+var page = template.Must(template.New("page").Funcs(template.FuncMap{"anchor": anchor}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 70em; padding: 0 1em; color: #24292f; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { border: 1px solid #d0d7de; padding: 6px 12px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+code { font-family: SFMono-Regular, Consolas, Menlo, monospace; font-size: 90%; }
+</style>
+</head>
+<body>
+{{- if .Title}}
+<h1>{{.Title}}</h1>
+{{- end}}
+{{- range .Sections}}
+{{- if .Name}}
+<h2 id="{{anchor .Name}}">{{.Name}}</h2>
+{{- end}}
+<table>
+<thead><tr><th>Key</th><th>Type</th><th>Required</th><th>Constraints</th><th>Default</th><th>Examples</th><th>Notes</th></tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr><td><code>{{.Key}}</code></td><td>{{.Type}}{{if .Ref}}{{if .Type}} {{end}}<a href="#{{anchor .Ref}}">{{.Ref}}</a>{{end}}</td><td>{{.Required}}</td><td>{{.Constraints}}</td><td>{{if .Default}}<code>{{.Default}}</code>{{end}}</td><td>{{if .Examples}}<code>{{.Examples}}</code>{{end}}</td><td>{{.Notes}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- if .Patterns}}
+<table>
+<thead><tr><th>Key pattern</th><th>Matching keys</th><th>Constraints</th></tr></thead>
+<tbody>
+{{- range .Patterns}}
+<tr><td><code>{{.Pattern}}</code></td><td>{{.Matches}}</td><td>{{.Constraints}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// HTML writes the document as a standalone HTML page
+func (d Document) HTML(w io.Writer) error {
+	return page.Execute(w, d)
+}
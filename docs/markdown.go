@@ -0,0 +1,86 @@
+package docs
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Markdown writes the document as Markdown, with a table of keys for each section
+func (d Document) Markdown(w io.Writer) error {
+	b := &strings.Builder{}
+
+	if d.Title != "" {
+		fmt.Fprintf(b, "# %s\n\n", d.Title)
+	}
+
+	for _, s := range d.Sections {
+		if s.Name != "" {
+			fmt.Fprintf(b, "## %s\n\n", s.Name)
+		}
+
+		b.WriteString("| Key | Type | Required | Constraints | Default | Examples | Notes |\n")
+		b.WriteString("| --- | --- | --- | --- | --- | --- | --- |\n")
+		for _, row := range s.Rows {
+			writeMarkdownRow(b, code(row.Key), markdownType(row), row.Required, row.Constraints, code(row.Default), code(row.Examples), row.Notes)
+		}
+
+		if len(s.Patterns) > 0 {
+			b.WriteString("\n| Key pattern | Matching keys | Constraints |\n")
+			b.WriteString("| --- | --- | --- |\n")
+			for _, p := range s.Patterns {
+				writeMarkdownRow(b, code(p.Pattern), p.Matches, p.Constraints)
+			}
+		}
+
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownRow(b *strings.Builder, cells ...string) {
+	for i, cell := range cells {
+		cells[i] = strings.ReplaceAll(strings.ReplaceAll(cell, "|", `\|`), "\n", " ")
+	}
+
+	fmt.Fprintf(b, "| %s |\n", strings.Join(cells, " | "))
+}
+
+// markdownType returns a row's type, linking to the section of a referenced validator
+func markdownType(row Row) string {
+	if row.Ref == "" {
+		return row.Type
+	}
+
+	link := fmt.Sprintf("[%s](#%s)", row.Ref, anchor(row.Ref))
+	if row.Type == "" {
+		return link
+	}
+
+	return row.Type + " " + link
+}
+
+func code(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	return "`" + s + "`"
+}
+
+// anchor returns the id of a section heading, the way GitHub generates it
+func anchor(name string) string {
+	id := strings.Builder{}
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			id.WriteRune(r)
+		case r == ' ':
+			id.WriteRune('-')
+		}
+	}
+
+	return id.String()
+}
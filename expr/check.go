@@ -0,0 +1,191 @@
+package expr
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Type is the type of a key or an expression
+type Type int
+
+const (
+	// Any is a type that's only known when the expression runs
+	Any Type = iota
+	Number
+	String
+	Bool
+	Time
+	List
+)
+
+func (t Type) String() string {
+	switch t {
+	case Number:
+		return "number"
+	case String:
+		return "string"
+	case Bool:
+		return "bool"
+	case Time:
+		return "time"
+	case List:
+		return "list"
+	}
+
+	return "any"
+}
+
+// function is a built in function. Its result type is known from its argument types
+type function struct {
+	params []Type
+	result Type
+	call   func(args []interface{}, env *environment) (interface{}, error)
+}
+
+// Env holds the keys an expression can use, and their types
+type Env map[string]Type
+
+type checker struct {
+	env    Env
+	errors ErrorList
+}
+
+// check returns the type of n, recording any errors
+func (c *checker) check(n node) Type {
+	switch n := n.(type) {
+	case *literal:
+		return typeOf(n.value)
+	case *ident:
+		t, ok := c.env[n.name]
+		if !ok {
+			c.errorf(n, "unknown key %q", n.name)
+		}
+
+		return t
+	case *list:
+		for _, elem := range n.elems {
+			c.check(elem)
+		}
+
+		return List
+	case *unary:
+		t := c.check(n.x)
+		if n.op == "!" {
+			c.expect(n.x, t, Bool)
+			return Bool
+		}
+
+		c.expect(n.x, t, Number)
+		return Number
+	case *binary:
+		return c.checkBinary(n)
+	case *call:
+		f, ok := functions[n.name]
+		if !ok {
+			c.errorf(n, "unknown function %q", n.name)
+			for _, arg := range n.args {
+				c.check(arg)
+			}
+
+			return Any
+		}
+
+		if len(n.args) != len(f.params) {
+			c.errorf(n, "%s takes %d arguments, not %d", n.name, len(f.params), len(n.args))
+		}
+
+		for i, arg := range n.args {
+			t := c.check(arg)
+			if i < len(f.params) {
+				c.expect(arg, t, f.params[i])
+			}
+		}
+
+		return f.result
+	}
+
+	return Any
+}
+
+func (c *checker) checkBinary(n *binary) Type {
+	x, y := c.check(n.x), c.check(n.y)
+
+	switch n.op {
+	case "&&", "||":
+		c.expect(n.x, x, Bool)
+		c.expect(n.y, y, Bool)
+		return Bool
+	case "==", "!=":
+		if x != Any && y != Any && x != y && !isNull(n.x) && !isNull(n.y) {
+			c.errorf(n, "can't compare %s and %s", x, y)
+		}
+
+		return Bool
+	case "<", "<=", ">", ">=":
+		c.expect(n.x, x, Number, String, Time)
+		if x != Any {
+			c.expect(n.y, y, x)
+		} else {
+			c.expect(n.y, y, Number, String, Time)
+		}
+
+		return Bool
+	case "matches":
+		c.expect(n.x, x, String)
+		c.expect(n.y, y, String)
+
+		if pattern, ok := n.y.(*literal); ok && y == String {
+			re, err := regexp.Compile(pattern.value.(string))
+			if err != nil {
+				c.errorf(n.y, "invalid pattern: %s", err)
+			}
+			n.re = re
+		}
+
+		return Bool
+	case "in":
+		c.expect(n.y, y, List)
+		return Bool
+	case "+":
+		c.expect(n.x, x, Number, String)
+		if x != Any {
+			c.expect(n.y, y, x)
+		} else {
+			c.expect(n.y, y, Number, String)
+		}
+
+		if x == Any {
+			return y
+		}
+
+		return x
+	}
+
+	c.expect(n.x, x, Number)
+	c.expect(n.y, y, Number)
+	return Number
+}
+
+// expect records an error if t isn't one of the wanted types. Any matches every type
+func (c *checker) expect(n node, t Type, wanted ...Type) {
+	if t == Any {
+		return
+	}
+
+	for _, w := range wanted {
+		if t == w || w == Any {
+			return
+		}
+	}
+
+	c.errorf(n, "expected %s, found %s", wanted[0], t)
+}
+
+func (c *checker) errorf(n node, format string, args ...interface{}) {
+	c.errors = append(c.errors, &Error{Pos: n.position(), Msg: fmt.Sprintf(format, args...)})
+}
+
+func isNull(n node) bool {
+	l, ok := n.(*literal)
+	return ok && l.value == nil
+}
@@ -0,0 +1,401 @@
+package expr
+
+import (
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// dateLayouts are the layouts strings are parsed with when a time is expected
+var dateLayouts = []string{time.RFC3339, "2006-01-02"}
+
+var functions = map[string]function{
+	"len": {params: []Type{Any}, result: Number, call: func(args []interface{}, _ *environment) (interface{}, error) {
+		switch v := args[0].(type) {
+		case string:
+			return float64(utf8.RuneCountInString(v)), nil
+		case []interface{}:
+			return float64(len(v)), nil
+		}
+
+		return nil, &Error{Msg: "len expects a string or a list, found " + typeOf(args[0]).String()}
+	}},
+	"lower": {params: []Type{String}, result: String, call: func(args []interface{}, _ *environment) (interface{}, error) {
+		return strings.ToLower(args[0].(string)), nil
+	}},
+	"upper": {params: []Type{String}, result: String, call: func(args []interface{}, _ *environment) (interface{}, error) {
+		return strings.ToUpper(args[0].(string)), nil
+	}},
+	"date": {params: []Type{String}, result: Time, call: func(args []interface{}, _ *environment) (interface{}, error) {
+		return parseTime(args[0].(string))
+	}},
+	"now": {params: []Type{}, result: Time, call: func(_ []interface{}, env *environment) (interface{}, error) {
+		return env.now, nil
+	}},
+	"year": {params: []Type{Time}, result: Number, call: func(args []interface{}, _ *environment) (interface{}, error) {
+		return float64(args[0].(time.Time).Year()), nil
+	}},
+	"month": {params: []Type{Time}, result: Number, call: func(args []interface{}, _ *environment) (interface{}, error) {
+		return float64(args[0].(time.Time).Month()), nil
+	}},
+	"day": {params: []Type{Time}, result: Number, call: func(args []interface{}, _ *environment) (interface{}, error) {
+		return float64(args[0].(time.Time).Day()), nil
+	}},
+	"add_days": {params: []Type{Time, Number}, result: Time, call: func(args []interface{}, _ *environment) (interface{}, error) {
+		return args[0].(time.Time).AddDate(0, 0, int(args[1].(float64))), nil
+	}},
+	"days_between": {params: []Type{Time, Time}, result: Number, call: func(args []interface{}, _ *environment) (interface{}, error) {
+		return math.Floor(args[1].(time.Time).Sub(args[0].(time.Time)).Hours() / 24), nil
+	}},
+}
+
+// environment is what an expression is evaluated against
+type environment struct {
+	values map[string]interface{}
+	now    time.Time
+}
+
+func (env *environment) eval(n node) (interface{}, error) {
+	switch n := n.(type) {
+	case *literal:
+		return n.value, nil
+	case *ident:
+		return normalize(env.lookup(n.name)), nil
+	case *list:
+		values := make([]interface{}, len(n.elems))
+		for i, elem := range n.elems {
+			v, err := env.eval(elem)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+
+		return values, nil
+	case *unary:
+		x, err := env.eval(n.x)
+		if err != nil {
+			return nil, err
+		}
+
+		if n.op == "!" {
+			b, err := as[bool](n.x, x, Bool)
+			return !b, err
+		}
+
+		f, err := as[float64](n.x, x, Number)
+		return -f, err
+	case *binary:
+		return env.evalBinary(n)
+	case *call:
+		f := functions[n.name]
+		args := make([]interface{}, len(n.args))
+		for i, arg := range n.args {
+			v, err := env.eval(arg)
+			if err != nil {
+				return nil, err
+			}
+
+			if args[i], err = convert(arg, v, f.params[i]); err != nil {
+				return nil, err
+			}
+		}
+
+		v, err := f.call(args, env)
+		if e, ok := err.(*Error); ok && e.Pos == 0 {
+			e.Pos = n.pos
+		}
+
+		return v, err
+	}
+
+	return nil, &Error{Pos: n.position(), Msg: "unknown expression"}
+}
+
+func (env *environment) evalBinary(n *binary) (interface{}, error) {
+	x, err := env.eval(n.x)
+	if err != nil {
+		return nil, err
+	}
+
+	// && and || only evaluate their right side when they need to
+	if n.op == "&&" || n.op == "||" {
+		left, err := as[bool](n.x, x, Bool)
+		if err != nil || left == (n.op == "||") {
+			return left, err
+		}
+
+		y, err := env.eval(n.y)
+		if err != nil {
+			return nil, err
+		}
+
+		return as[bool](n.y, y, Bool)
+	}
+
+	y, err := env.eval(n.y)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(x, y), nil
+	case "!=":
+		return !equal(x, y), nil
+	case "<", "<=", ">", ">=":
+		c, err := compareValues(n, x, y)
+		if err != nil {
+			return nil, err
+		}
+
+		switch n.op {
+		case "<":
+			return c < 0, nil
+		case "<=":
+			return c <= 0, nil
+		case ">":
+			return c > 0, nil
+		}
+
+		return c >= 0, nil
+	case "matches":
+		s, err := as[string](n.x, x, String)
+		if err != nil {
+			return nil, err
+		}
+
+		re := n.re
+		if re == nil {
+			pattern, err := as[string](n.y, y, String)
+			if err != nil {
+				return nil, err
+			}
+
+			if re, err = regexp.Compile(pattern); err != nil {
+				return nil, &Error{Pos: n.y.position(), Msg: "invalid pattern: " + err.Error()}
+			}
+		}
+
+		return re.MatchString(s), nil
+	case "in":
+		elems, err := as[[]interface{}](n.y, y, List)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, elem := range elems {
+			if equal(x, elem) {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	case "+":
+		if s, ok := x.(string); ok {
+			t, err := as[string](n.y, y, String)
+			return s + t, err
+		}
+	}
+
+	left, err := as[float64](n.x, x, Number)
+	if err != nil {
+		return nil, err
+	}
+
+	right, err := as[float64](n.y, y, Number)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "+":
+		return left + right, nil
+	case "-":
+		return left - right, nil
+	case "*":
+		return left * right, nil
+	case "/":
+		if right == 0 {
+			return nil, &Error{Pos: n.pos, Msg: "division by zero"}
+		}
+
+		return left / right, nil
+	}
+
+	if right == 0 {
+		return nil, &Error{Pos: n.pos, Msg: "division by zero"}
+	}
+
+	return math.Mod(left, right), nil
+}
+
+// lookup returns the value of a key. Dotted keys like "address.city" that aren't in values are
+// looked up in nested maps
+func (env *environment) lookup(name string) interface{} {
+	if v, ok := env.values[name]; ok {
+		return v
+	}
+
+	var v interface{} = env.values
+	for _, part := range strings.Split(name, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[part]
+	}
+
+	return v
+}
+
+// as asserts that a value has type T
+func as[T any](n node, v interface{}, t Type) (T, error) {
+	converted, err := convert(n, v, t)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return converted.(T), nil
+}
+
+// convert checks that a value has type t. Strings are parsed when a time is expected
+func convert(n node, v interface{}, t Type) (interface{}, error) {
+	if t == Any || typeOf(v) == t {
+		return v, nil
+	}
+
+	if s, ok := v.(string); ok && t == Time {
+		parsed, err := parseTime(s)
+		if err != nil {
+			err.(*Error).Pos = n.position()
+		}
+
+		return parsed, err
+	}
+
+	return nil, &Error{Pos: n.position(), Msg: "expected " + t.String() + ", found " + describeValue(v)}
+}
+
+func compareValues(n *binary, x interface{}, y interface{}) (int, error) {
+	t := typeOf(x)
+	if t == String && typeOf(y) == Time {
+		t = Time
+	}
+
+	switch t {
+	case Number:
+		right, err := as[float64](n.y, y, Number)
+		return compareOrdered(x.(float64), right), err
+	case String:
+		right, err := as[string](n.y, y, String)
+		return compareOrdered(x.(string), right), err
+	case Time:
+		left, err := as[time.Time](n.x, x, Time)
+		if err != nil {
+			return 0, err
+		}
+
+		right, err := as[time.Time](n.y, y, Time)
+		return left.Compare(right), err
+	}
+
+	return 0, &Error{Pos: n.x.position(), Msg: "can't order " + describeValue(x)}
+}
+
+func compareOrdered[T float64 | string](x T, y T) int {
+	if x < y {
+		return -1
+	} else if x > y {
+		return 1
+	}
+
+	return 0
+}
+
+func equal(x interface{}, y interface{}) bool {
+	left, leftIsTime := x.(time.Time)
+	right, rightIsTime := y.(time.Time)
+
+	if leftIsTime || rightIsTime {
+		var err error
+		if s, ok := x.(string); ok {
+			left, err = parseTime(s)
+		} else if s, ok := y.(string); ok {
+			right, err = parseTime(s)
+		} else if !leftIsTime || !rightIsTime {
+			return false
+		}
+
+		return err == nil && left.Equal(right)
+	}
+
+	return reflect.DeepEqual(x, y)
+}
+
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, &Error{Msg: "invalid date " + s}
+}
+
+// normalize converts numbers to float64 and slices to []interface{}, so values from the input have
+// the same types as values in the expression
+func normalize(v interface{}) interface{} {
+	value := reflect.ValueOf(v)
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		return value.Float()
+	case reflect.Slice, reflect.Array:
+		values := make([]interface{}, value.Len())
+		for i := range values {
+			values[i] = normalize(value.Index(i).Interface())
+		}
+
+		return values
+	}
+
+	return v
+}
+
+func typeOf(v interface{}) Type {
+	switch v.(type) {
+	case float64:
+		return Number
+	case string:
+		return String
+	case bool:
+		return Bool
+	case time.Time:
+		return Time
+	case []interface{}:
+		return List
+	}
+
+	return Any
+}
+
+func describeValue(v interface{}) string {
+	if v == nil {
+		return "null"
+	}
+
+	if t := typeOf(v); t != Any {
+		return t.String()
+	}
+
+	return reflect.TypeOf(v).String()
+}
@@ -0,0 +1,176 @@
+// Package expr is a small expression language for writing constraints over an input map without Go,
+// like:
+//
+//	qty * unit_price <= credit_limit && (country != "US" || len(zip) == 5)
+//
+// Expressions have numbers, strings, booleans, times and lists, with arithmetic, comparisons,
+// && || and !, "matches" for regular expressions, "in" for list membership, and the functions len,
+// lower, upper, date, now, year, month, day, add_days and days_between. Expressions are type
+// checked when they're compiled, against the keys and types in an Env. Using a key that isn't in the
+// Env is a compile error.
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nmante/validator"
+	"github.com/nmante/validator/funcs"
+)
+
+// ValueKey is the key a per key Func binds its value to
+const ValueKey = "value"
+
+var (
+	ErrNotBool = errors.New("Expression must be a boolean")
+)
+
+// Error is a compile or runtime error. Pos is the 1 based column in the expression it refers to
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d: %s", e.Pos, e.Msg)
+}
+
+// ErrorList is every error found when compiling an expression
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	messages := make([]string, len(l))
+	for i, e := range l {
+		messages[i] = e.Error()
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Expr is a compiled expression. It's safe to evaluate concurrently
+type Expr struct {
+	source string
+	root   node
+	_type  Type
+}
+
+// Compile parses and type checks an expression. Syntax errors are returned as an *Error, and type
+// errors, like unknown keys, as an ErrorList
+func Compile(source string, env Env) (*Expr, error) {
+	root, err := parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &checker{env: env}
+	t := c.check(root)
+	if len(c.errors) > 0 {
+		return nil, c.errors
+	}
+
+	return &Expr{source: source, root: root, _type: t}, nil
+}
+
+// CompileFunc compiles an expression over a single value, bound to ValueKey, for use as a Func
+func CompileFunc(source string, valueType Type) (*Expr, error) {
+	return Compile(source, Env{ValueKey: valueType})
+}
+
+// MustCompile is like Compile but panics if the expression can't be compiled
+func MustCompile(source string, env Env) *Expr {
+	e, err := Compile(source, env)
+	if err != nil {
+		panic(fmt.Sprintf("expr: Compile(%q): %s", source, err))
+	}
+
+	return e
+}
+
+// String returns the expression's source
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Type returns the type of the expression's result. It's Any if that depends on the input
+func (e *Expr) Type() Type {
+	return e._type
+}
+
+// Eval evaluates the expression against values. Numbers are returned as float64, and lists as
+// []interface{}. Using a value of the wrong type, like a missing key in arithmetic, is an *Error
+func (e *Expr) Eval(values map[string]interface{}) (interface{}, error) {
+	return (&environment{values: values, now: time.Now()}).eval(e.root)
+}
+
+// EvalBool evaluates an expression that returns a boolean
+func (e *Expr) EvalBool(values map[string]interface{}) (bool, error) {
+	v, err := e.Eval(values)
+	if err != nil {
+		return false, err
+	}
+
+	b, ok := v.(bool)
+	if !ok {
+		return false, ErrNotBool
+	}
+
+	return b, nil
+}
+
+// RecordFunc returns a validator.RecordFunc that adds message under key when the expression is
+// false, or when it can't be evaluated because a value is missing or has the wrong type. Use
+// validator.FormKey for constraints that span many keys
+func (e *Expr) RecordFunc(key string, message string) validator.RecordFunc {
+	return func(values map[string]interface{}) (validator.Response, error) {
+		response := validator.Response{Errors: map[string][]string{}, IsValid: true}
+
+		ok, err := e.check(values)
+		if err != nil {
+			return validator.Response{}, err
+		}
+
+		if !ok {
+			response.AddError(key, message)
+		}
+
+		return response, nil
+	}
+}
+
+// Func returns a funcs.Func that checks a value with an expression compiled by CompileFunc, and
+// fails with message when the expression is false or can't be evaluated, like RecordFunc
+func (e *Expr) Func(message string) funcs.Func {
+	meta := funcs.Meta{
+		Name:        "Expr",
+		Description: message,
+		Params:      []funcs.Param{funcs.Param{Name: "expression", Value: e.source}},
+	}
+
+	return funcs.WithMeta(meta, func(v interface{}) (funcs.Response, error) {
+		ok, err := e.check(map[string]interface{}{ValueKey: v})
+		if err != nil {
+			return funcs.Response{}, err
+		}
+
+		if !ok {
+			return funcs.Response{IsValid: false, Error: message}, nil
+		}
+
+		return funcs.Response{IsValid: true}, nil
+	})
+}
+
+// check evaluates a constraint. Runtime errors in the input, like a missing key, make it false;
+// only an expression that isn't a boolean is an error
+func (e *Expr) check(values map[string]interface{}) (bool, error) {
+	ok, err := e.EvalBool(values)
+
+	var exprErr *Error
+	if errors.As(err, &exprErr) {
+		return false, nil
+	}
+
+	return ok, err
+}
@@ -0,0 +1,201 @@
+package expr
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/nmante/validator"
+	"github.com/nmante/validator/funcs"
+)
+
+var orderEnv = Env{
+	"qty":          Number,
+	"unit_price":   Number,
+	"credit_limit": Number,
+	"country":      String,
+	"zip":          String,
+	"tags":         List,
+	"ships_at":     Time,
+	"address.city": String,
+	"extra":        Any,
+}
+
+func TestEval(t *testing.T) {
+	values := map[string]interface{}{
+		"qty":          3,
+		"unit_price":   2.5,
+		"credit_limit": uint(10),
+		"country":      "US",
+		"zip":          "94107",
+		"tags":         []string{"gift", "fragile"},
+		"ships_at":     "2024-03-01",
+		"address":      map[string]interface{}{"city": "Oakland"},
+	}
+
+	evalTests := []struct {
+		source   string
+		expected interface{}
+	}{
+		{source: `qty * unit_price <= credit_limit && (country != "US" || len(zip) == 5)`, expected: true},
+		{source: `qty * unit_price`, expected: 7.5},
+		{source: `1 + 2 * 3 - 4 / 2`, expected: 5.0},
+		{source: `-qty % 2`, expected: -1.0},
+		{source: `"a" + "b"`, expected: "ab"},
+		{source: `!(qty > 2)`, expected: false},
+		{source: `zip matches "^[0-9]{5}$"`, expected: true},
+		{source: `country in ["US", "CA"]`, expected: true},
+		{source: `qty in [1, 2]`, expected: false},
+		{source: `"gift" in tags`, expected: true},
+		{source: `len(tags)`, expected: 2.0},
+		{source: `ships_at < date("2024-04-01")`, expected: true},
+		{source: `ships_at == date("2024-03-01T00:00:00Z")`, expected: true},
+		{source: `year(ships_at) * 100 + month(ships_at)`, expected: 202403.0},
+		{source: `days_between(ships_at, add_days(ships_at, 10))`, expected: 10.0},
+		{source: `date("2000-01-01") < now()`, expected: true},
+		{source: `address.city == "Oakland"`, expected: true},
+		{source: `lower(country) == "us" && upper("ok") == "OK"`, expected: true},
+		{source: `extra == null`, expected: true},
+		{source: `extra == null || extra > 3`, expected: true},
+		{source: `[1, "a"]`, expected: []interface{}{1.0, "a"}},
+	}
+
+	for _, test := range evalTests {
+		e, err := Compile(test.source, orderEnv)
+		if err != nil {
+			t.Errorf("%s: %s", test.source, err)
+			continue
+		}
+
+		v, err := e.Eval(values)
+		if err != nil {
+			t.Errorf("%s: %s", test.source, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(v, test.expected) {
+			t.Errorf("%s should be %v. It's %v", test.source, test.expected, v)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	compileTests := []struct {
+		source   string
+		expected string
+	}{
+		{source: `qtty * unit_pirce > 1`, expected: `1: unknown key "qtty"; 8: unknown key "unit_pirce"`},
+		{source: `qty > "3"`, expected: `7: expected number, found string`},
+		{source: `country == 1`, expected: `9: can't compare string and number`},
+		{source: `len(zip, 1) == 5`, expected: `1: len takes 1 arguments, not 2`},
+		{source: `size(zip)`, expected: `1: unknown function "size"`},
+		{source: `zip matches "("`, expected: "13: invalid pattern: error parsing regexp: missing closing ): `(`"},
+		{source: `qty in 3`, expected: `8: expected list, found number`},
+		{source: `qty && true`, expected: `1: expected bool, found number`},
+		{source: `qty >`, expected: `6: unexpected end of expression`},
+		{source: `(qty > 1`, expected: `9: expected ), found end of expression`},
+		{source: `qty # 1`, expected: `5: unexpected character '#'`},
+		{source: `"open`, expected: `1: unterminated string`},
+		{source: `[1, 2 3]`, expected: `7: expected , or ], found 3`},
+	}
+
+	for _, test := range compileTests {
+		_, err := Compile(test.source, orderEnv)
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("%s: error should be %q. It's %v", test.source, test.expected, err)
+		}
+	}
+}
+
+func TestUnicodeIdentifiers(t *testing.T) {
+	env := Env{"größe": Number, "名前": String}
+
+	ok, err := MustCompile(`größe > 1 && 名前 == "ok"`, env).EvalBool(map[string]interface{}{"größe": 2, "名前": "ok"})
+	if err != nil || !ok {
+		t.Errorf("Expression should be true. It's %t, %v", ok, err)
+	}
+
+	_, err = Compile(`größe > "a"`, env)
+	if err == nil || err.Error() != `9: expected number, found string` {
+		t.Errorf("Error should count columns in runes. It's %v", err)
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	e := MustCompile(`qty * unit_price > 1`, orderEnv)
+
+	_, err := e.Eval(map[string]interface{}{"qty": 1})
+	var exprErr *Error
+	if !errors.As(err, &exprErr) || exprErr.Error() != "7: expected number, found null" {
+		t.Errorf("Error should be about unit_price. It's %v", err)
+	}
+
+	_, err = MustCompile(`ships_at < now()`, orderEnv).Eval(map[string]interface{}{"ships_at": "soon"})
+	if err == nil || err.Error() != "1: invalid date soon" {
+		t.Errorf("Error should be about the date. It's %v", err)
+	}
+
+	if _, err := MustCompile(`qty + 1`, orderEnv).EvalBool(map[string]interface{}{"qty": 1}); err != ErrNotBool {
+		t.Errorf("Error should be ErrNotBool. It's %v", err)
+	}
+}
+
+func TestRecordFunc(t *testing.T) {
+	e := MustCompile(`qty * unit_price <= credit_limit`, orderEnv)
+
+	v, _ := validator.New([]validator.Rule{})
+	v.AddRecordRule(e.RecordFunc(validator.FormKey, "exceeds credit limit"), false)
+
+	r, err := v.Validate(map[string]interface{}{"qty": 5, "unit_price": 3, "credit_limit": 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []validator.FieldError{validator.FieldError{Key: validator.FormKey, Message: "exceeds credit limit"}}
+	if r.IsValid || !reflect.DeepEqual(r.FieldErrors(), expected) {
+		t.Errorf("Errors should be %+v. They are %+v", expected, r.FieldErrors())
+	}
+
+	r, _ = v.Validate(map[string]interface{}{"qty": 2, "unit_price": 3, "credit_limit": 10})
+	if !r.IsValid {
+		t.Errorf("Response should be valid. Errors are %+v", r.FieldErrors())
+	}
+
+	zip := MustCompile(`country != "US" || len(zip) == 5`, orderEnv)
+	v, _ = validator.New([]validator.Rule{})
+	v.AddRecordRule(zip.RecordFunc("zip", "must have 5 digits"), false)
+
+	r, err = v.Validate(map[string]interface{}{"country": "US"})
+	if err != nil {
+		t.Fatalf("A missing key should fail the rule, not return an error. It returned %v", err)
+	}
+
+	expected = []validator.FieldError{validator.FieldError{Key: "zip", Message: "must have 5 digits"}}
+	if r.IsValid || !reflect.DeepEqual(r.FieldErrors(), expected) {
+		t.Errorf("Errors should be %+v. They are %+v", expected, r.FieldErrors())
+	}
+}
+
+func TestFunc(t *testing.T) {
+	e, err := CompileFunc(`value matches "^[A-Z]{2}$" && value != "XX"`, String)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, _ := validator.New([]validator.Rule{validator.Rule{Key: "country", Funcs: []funcs.Func{e.Func("must be a country code")}}})
+
+	for country, valid := range map[interface{}]bool{"US": true, "usa": false, "XX": false, 12: false} {
+		r, err := v.Validate(map[string]interface{}{"country": country})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if r.IsValid != valid {
+			t.Errorf("%v: IsValid should be %t. Errors are %+v", country, valid, r.FieldErrors())
+		}
+	}
+
+	if _, err := CompileFunc(`len(values) > 1`, String); err == nil {
+		t.Error("Unknown key should be a compile error")
+	}
+}
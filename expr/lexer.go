@@ -0,0 +1,117 @@
+package expr
+
+import (
+	"strconv"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value interface{}
+	pos   int
+}
+
+// operators are checked longest first, so "<=" isn't read as "<" then "="
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "+", "-", "*", "/", "%", "!", "(", ")", "[", "]", ","}
+
+// lex splits source into tokens. Positions are 1 based columns, counted in runes
+func lex(source string) ([]token, error) {
+	tokens := []token{}
+	src := []rune(source)
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case isDigit(c) || c == '.' && i+1 < len(src) && isDigit(src[i+1]):
+			start := i
+			for i < len(src) && (isDigit(src[i]) || src[i] == '.') {
+				i++
+			}
+
+			text := string(src[start:i])
+			n, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, &Error{Pos: start + 1, Msg: "invalid number " + text}
+			}
+
+			tokens = append(tokens, token{kind: tokenNumber, text: text, value: n, pos: start + 1})
+		case c == '"':
+			start := i
+			for i++; i < len(src) && src[i] != '"'; i++ {
+				if src[i] == '\\' {
+					i++
+				}
+			}
+
+			if i >= len(src) {
+				return nil, &Error{Pos: start + 1, Msg: "unterminated string"}
+			}
+
+			i++
+			text := string(src[start:i])
+			s, err := strconv.Unquote(text)
+			if err != nil {
+				return nil, &Error{Pos: start + 1, Msg: "invalid string " + text}
+			}
+
+			tokens = append(tokens, token{kind: tokenString, text: text, value: s, pos: start + 1})
+		case c == '_' || unicode.IsLetter(c):
+			start := i
+			for i < len(src) && (src[i] == '_' || src[i] == '.' || isDigit(src[i]) || unicode.IsLetter(src[i])) {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: tokenIdent, text: string(src[start:i]), pos: start + 1})
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if hasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+
+			if op == "" {
+				return nil, &Error{Pos: i + 1, Msg: "unexpected character " + strconv.QuoteRune(c)}
+			}
+
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i + 1})
+			i += len(op)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(src) + 1}), nil
+}
+
+// hasPrefix checks if src starts with an ASCII operator
+func hasPrefix(src []rune, op string) bool {
+	if len(src) < len(op) {
+		return false
+	}
+
+	for i := 0; i < len(op); i++ {
+		if src[i] != rune(op[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isDigit(c rune) bool {
+	return c >= '0' && c <= '9'
+}
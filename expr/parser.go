@@ -0,0 +1,250 @@
+package expr
+
+import (
+	"regexp"
+)
+
+// node is a node of an expression's syntax tree
+type node interface {
+	position() int
+}
+
+type literal struct {
+	pos   int
+	value interface{}
+}
+
+type ident struct {
+	pos  int
+	name string
+}
+
+type unary struct {
+	pos int
+	op  string
+	x   node
+}
+
+type binary struct {
+	pos int
+	op  string
+	x   node
+	y   node
+	// re is the compiled pattern when the right side of "matches" is a literal
+	re *regexp.Regexp
+}
+
+type call struct {
+	pos  int
+	name string
+	args []node
+}
+
+type list struct {
+	pos   int
+	elems []node
+}
+
+func (n *literal) position() int { return n.pos }
+func (n *ident) position() int   { return n.pos }
+func (n *unary) position() int   { return n.pos }
+func (n *binary) position() int  { return n.pos }
+func (n *call) position() int    { return n.pos }
+func (n *list) position() int    { return n.pos }
+
+// precedence is the binding power of binary operators. Higher binds tighter
+var precedence = map[string]int{
+	"||":      1,
+	"&&":      2,
+	"==":      3,
+	"!=":      3,
+	"<":       3,
+	"<=":      3,
+	">":       3,
+	">=":      3,
+	"matches": 3,
+	"in":      3,
+	"+":       4,
+	"-":       4,
+	"*":       5,
+	"/":       5,
+	"%":       5,
+}
+
+type parser struct {
+	tokens []token
+	i      int
+}
+
+func parse(source string) (node, error) {
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	n, err := p.expression(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &Error{Pos: t.pos, Msg: "unexpected " + describe(t)}
+	}
+
+	return n, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
+	}
+
+	return t
+}
+
+func (p *parser) expect(op string) error {
+	if t := p.next(); t.kind != tokenOperator || t.text != op {
+		return &Error{Pos: t.pos, Msg: "expected " + op + ", found " + describe(t)}
+	}
+
+	return nil
+}
+
+// binaryOperator returns the operator at the current token, if there is one
+func (p *parser) binaryOperator() (string, bool) {
+	t := p.peek()
+	if t.kind != tokenOperator && t.kind != tokenIdent {
+		return "", false
+	}
+
+	_, ok := precedence[t.text]
+	return t.text, ok
+}
+
+// expression parses binary operators that bind at least as tightly as min
+func (p *parser) expression(min int) (node, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op, ok := p.binaryOperator()
+		if !ok || precedence[op] < min {
+			return x, nil
+		}
+
+		t := p.next()
+		y, err := p.expression(precedence[op] + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		x = &binary{pos: t.pos, op: op, x: x, y: y}
+	}
+}
+
+func (p *parser) unary() (node, error) {
+	if t := p.peek(); t.kind == tokenOperator && (t.text == "!" || t.text == "-") {
+		p.next()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &unary{pos: t.pos, op: t.text, x: x}, nil
+	}
+
+	return p.primary()
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokenNumber, tokenString:
+		return &literal{pos: t.pos, value: t.value}, nil
+	case tokenIdent:
+		switch t.text {
+		case "true", "false":
+			return &literal{pos: t.pos, value: t.text == "true"}, nil
+		case "null":
+			return &literal{pos: t.pos, value: nil}, nil
+		}
+
+		if next := p.peek(); next.kind == tokenOperator && next.text == "(" {
+			p.next()
+			args, err := p.elements(")")
+			if err != nil {
+				return nil, err
+			}
+
+			return &call{pos: t.pos, name: t.text, args: args}, nil
+		}
+
+		return &ident{pos: t.pos, name: t.text}, nil
+	case tokenOperator:
+		switch t.text {
+		case "(":
+			x, err := p.expression(1)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+
+			return x, nil
+		case "[":
+			elems, err := p.elements("]")
+			if err != nil {
+				return nil, err
+			}
+
+			return &list{pos: t.pos, elems: elems}, nil
+		}
+	}
+
+	return nil, &Error{Pos: t.pos, Msg: "unexpected " + describe(t)}
+}
+
+// elements parses comma separated expressions up to a closing bracket
+func (p *parser) elements(closing string) ([]node, error) {
+	elems := []node{}
+	if t := p.peek(); t.kind == tokenOperator && t.text == closing {
+		p.next()
+		return elems, nil
+	}
+
+	for {
+		x, err := p.expression(1)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, x)
+
+		t := p.next()
+		if t.kind == tokenOperator && t.text == closing {
+			return elems, nil
+		}
+
+		if t.kind != tokenOperator || t.text != "," {
+			return nil, &Error{Pos: t.pos, Msg: "expected , or " + closing + ", found " + describe(t)}
+		}
+	}
+}
+
+func describe(t token) string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+
+	return t.text
+}
@@ -0,0 +1,36 @@
+package validator
+
+import (
+	"github.com/nmante/validator/funcs"
+)
+
+// Field is a Rule for values of type T. Its Funcs are checked against T by the compiler, and values
+// that aren't a T are invalid
+type Field[T any] struct {
+	Key            string
+	IsRequired     bool
+	EnableParallel bool
+	Bail           bool
+	Funcs          []funcs.TypedFunc[T]
+}
+
+// NewField returns a Field for a key
+func NewField[T any](key string, fns ...funcs.TypedFunc[T]) Field[T] {
+	return Field[T]{Key: key, Funcs: fns}
+}
+
+// Rule converts the Field to a Rule
+func (f Field[T]) Rule() Rule {
+	fns := make([]funcs.Func, len(f.Funcs))
+	for i, typed := range f.Funcs {
+		fns[i] = funcs.Untyped(typed)
+	}
+
+	return Rule{
+		Key:            f.Key,
+		IsRequired:     f.IsRequired,
+		EnableParallel: f.EnableParallel,
+		Bail:           f.Bail,
+		Funcs:          fns,
+	}
+}
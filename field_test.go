@@ -0,0 +1,44 @@
+package validator
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/nmante/validator/funcs"
+)
+
+func TestField(t *testing.T) {
+	status := NewField("status", funcs.OneOf("draft", "submitted", "shipped"))
+	status.IsRequired = true
+
+	validator, _ := New([]Rule{
+		status.Rule(),
+		NewField("page_size", funcs.Convert(strconv.Atoi, funcs.Between(1, 100))).Rule(),
+		NewField("count", funcs.Min(int64(0))).Rule(),
+	})
+
+	r, err := validator.Validate(map[string]interface{}{
+		"status":    "cancelled",
+		"page_size": "50",
+		"count":     5,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := []FieldError{
+		FieldError{Key: "status", Message: "must be one of draft, submitted, shipped"},
+		FieldError{Key: "count", Message: "must be a int64"},
+	}
+
+	fieldErrors := r.FieldErrors()
+	if len(fieldErrors) != len(expected) {
+		t.Fatalf("Errors should be %+v. They are %+v", expected, fieldErrors)
+	}
+
+	for i := range expected {
+		if fieldErrors[i] != expected[i] {
+			t.Errorf("Error should be %+v. It is %+v", expected[i], fieldErrors[i])
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package funcs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/nmante/validator/compare"
+)
+
+var (
+	ErrNotCollection = errors.New("Value must be a slice, array or map")
+)
+
+// element is an element of a slice or array, or an entry of a map
+type element struct {
+	path  string
+	key   interface{}
+	value interface{}
+}
+
+// elementsOf returns the elements of a slice, array or map. Map entries are sorted by key, with
+// numeric keys sorted as numbers
+func elementsOf(v interface{}) ([]element, error) {
+	value := reflect.ValueOf(v)
+
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		elements := make([]element, value.Len())
+		for i := range elements {
+			elements[i] = element{path: fmt.Sprintf("[%d]", i), key: i, value: value.Index(i).Interface()}
+		}
+
+		return elements, nil
+	case reflect.Map:
+		elements := make([]element, 0, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			key := iter.Key().Interface()
+			elements = append(elements, element{path: fmt.Sprintf("[%v]", key), key: key, value: iter.Value().Interface()})
+		}
+
+		sort.Slice(elements, func(i, j int) bool { return lessKey(elements[i], elements[j]) })
+		return elements, nil
+	}
+
+	return nil, ErrNotCollection
+}
+
+// lessKey orders map entries by key. Keys that aren't both numbers of the same kind are ordered by
+// their paths
+func lessKey(a element, b element) bool {
+	left, right := reflect.ValueOf(a.key), reflect.ValueOf(b.key)
+
+	switch {
+	case left.CanInt() && right.CanInt():
+		return left.Int() < right.Int()
+	case left.CanUint() && right.CanUint():
+		return left.Uint() < right.Uint()
+	case left.CanFloat() && right.CanFloat():
+		return left.Float() < right.Float()
+	}
+
+	return a.path < b.path
+}
+
+// nestedResponse returns a Response for a collection with invalid elements. Elements with only
+// warnings or infos don't make it invalid
+func nestedResponse(nested []PathError) Response {
+	if len(nested) == 0 {
+		return Response{IsValid: true}
+	}
+
+	messages := []string{}
+	for _, pe := range nested {
+		if pe.Severity == SeverityError {
+			messages = append(messages, pe.Path+" "+pe.Error)
+		}
+	}
+
+	if len(messages) == 0 {
+		return Response{IsValid: true, Nested: nested}
+	}
+
+	return Response{
+		IsValid: false,
+		Error:   "has invalid elements: " + strings.Join(messages, ", "),
+		Nested:  nested,
+	}
+}
+
+// each runs fns on part of every element, reporting failures with the element's path
+func each(v interface{}, part func(element) interface{}, fns []Func) (Response, error) {
+	elements, err := elementsOf(v)
+	if err != nil {
+		return Response{}, err
+	}
+
+	nested := []PathError{}
+	for _, e := range elements {
+		for _, f := range fns {
+			r, err := f(part(e))
+			if err != nil {
+				return Response{}, fmt.Errorf("%s: %w", e.path, err)
+			}
+
+			if !r.IsValid && len(r.Nested) == 0 {
+				nested = append(nested, PathError{Path: e.path, Error: r.Error, Severity: r.Severity})
+			}
+
+			for _, pe := range r.Nested {
+				nested = append(nested, PathError{Path: joinPath(e.path, pe.Path), Error: pe.Error, Severity: r.SeverityOf(pe)})
+			}
+		}
+	}
+
+	return nestedResponse(nested), nil
+}
+
+// joinPath joins an element path like "[2]" with a path within the element like "city" or "[0]"
+func joinPath(path string, child string) string {
+	if child == "" || strings.HasPrefix(child, "[") {
+		return path + child
+	}
+
+	return path + "." + child
+}
+
+// Each runs fns on every element of a slice or array, or every value of a map
+func Each(fns ...Func) Func {
+	return WithMeta(Meta{Name: "Each", Children: describeAll(fns...)}, func(v interface{}) (Response, error) {
+		return each(v, func(e element) interface{} { return e.value }, fns)
+	})
+}
+
+// EachKey runs fns on every key of a map
+func EachKey(fns ...Func) Func {
+	return WithMeta(Meta{Name: "EachKey", Children: describeAll(fns...)}, func(v interface{}) (Response, error) {
+		if reflect.ValueOf(v).Kind() != reflect.Map {
+			return Response{}, ErrNotCollection
+		}
+
+		return each(v, func(e element) interface{} { return e.key }, fns)
+	})
+}
+
+// EachValue runs fns on every value of a map
+func EachValue(fns ...Func) Func {
+	return WithMeta(Meta{Name: "EachValue", Children: describeAll(fns...)}, func(v interface{}) (Response, error) {
+		if reflect.ValueOf(v).Kind() != reflect.Map {
+			return Response{}, ErrNotCollection
+		}
+
+		return each(v, func(e element) interface{} { return e.value }, fns)
+	})
+}
+
+// Unique checks that no two elements of a collection are equal
+func Unique(v interface{}) (Response, error) {
+	return UniqueBy(func(e interface{}) interface{} { return e })(v)
+}
+
+// UniqueBy checks that no two elements of a collection have equal keys, as returned by keyFn
+func UniqueBy(keyFn func(interface{}) interface{}) Func {
+	return WithMeta(Meta{Name: "UniqueBy", Description: "must have unique elements", ErrorCodes: []string{"unique"}}, func(v interface{}) (Response, error) {
+		elements, err := elementsOf(v)
+		if err != nil {
+			return Response{}, err
+		}
+
+		nested := []PathError{}
+		seen := map[interface{}]string{}
+		// keys that can't be map keys are compared one by one
+		unhashable := []element{}
+
+		for _, e := range elements {
+			key := keyFn(e.value)
+
+			if key == nil || reflect.ValueOf(key).Comparable() {
+				if path, ok := seen[key]; ok {
+					nested = append(nested, PathError{Path: e.path, Error: "must be unique, duplicates " + path})
+					continue
+				}
+
+				seen[key] = e.path
+				continue
+			}
+
+			duplicate := false
+			for _, other := range unhashable {
+				if reflect.DeepEqual(key, other.key) {
+					nested = append(nested, PathError{Path: e.path, Error: "must be unique, duplicates " + other.path})
+					duplicate = true
+					break
+				}
+			}
+
+			if !duplicate {
+				unhashable = append(unhashable, element{path: e.path, key: key})
+			}
+		}
+
+		return nestedResponse(nested), nil
+	})
+}
+
+// Contains checks that a collection has an element equal to value
+func Contains(value interface{}) Func {
+	return WithMeta(Meta{
+		Name:        "Contains",
+		Description: fmt.Sprintf("must contain %v", value),
+		Params:      []Param{Param{Name: "value", Value: value}},
+		ErrorCodes:  []string{"contains"},
+	}, func(v interface{}) (Response, error) {
+		elements, err := elementsOf(v)
+		if err != nil {
+			return Response{}, err
+		}
+
+		for _, e := range elements {
+			if reflect.DeepEqual(e.value, value) {
+				return Response{IsValid: true}, nil
+			}
+		}
+
+		return Response{IsValid: false, Error: fmt.Sprintf("must contain %v", value)}, nil
+	})
+}
+
+// SubsetOf checks that every element of a collection is one of values
+func SubsetOf(values ...interface{}) Func {
+	allowed := make([]string, len(values))
+	for i, value := range values {
+		allowed[i] = fmt.Sprint(value)
+	}
+	message := "must be one of " + strings.Join(allowed, ", ")
+
+	return WithMeta(Meta{
+		Name:        "SubsetOf",
+		Description: "elements " + message,
+		Params:      []Param{Param{Name: "values", Value: values}},
+		ErrorCodes:  []string{"one_of"},
+	}, func(v interface{}) (Response, error) {
+		elements, err := elementsOf(v)
+		if err != nil {
+			return Response{}, err
+		}
+
+		nested := []PathError{}
+		for _, e := range elements {
+			found := false
+			for _, value := range values {
+				if reflect.DeepEqual(e.value, value) {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				nested = append(nested, PathError{Path: e.path, Error: message})
+			}
+		}
+
+		return nestedResponse(nested), nil
+	})
+}
+
+// Sorted checks that the elements of a slice or array are in ascending order. Elements the comparer
+// can't compare, like float64s with compare.Int, aren't valid
+func Sorted(comparer compare.Interface) Func {
+	return WithMeta(Meta{Name: "Sorted", Description: "must be sorted", ErrorCodes: []string{"sorted"}}, func(v interface{}) (Response, error) {
+		if kind := reflect.ValueOf(v).Kind(); kind != reflect.Slice && kind != reflect.Array {
+			return Response{}, ErrNotCollection
+		}
+
+		elements, err := elementsOf(v)
+		if err != nil {
+			return Response{}, err
+		}
+
+		for i := 1; i < len(elements); i++ {
+			if ok, _ := isTypesEqual(elements[i-1].value, elements[i].value); !ok {
+				return nestedResponse([]PathError{
+					PathError{Path: elements[i].path, Error: "must have the same type as " + elements[i-1].path},
+				}), nil
+			}
+
+			c, err := compare.Try(comparer, elements[i-1].value, elements[i].value)
+			if err != nil {
+				return nestedResponse([]PathError{
+					PathError{Path: elements[i].path, Error: fmt.Sprintf("can't compare %T values", elements[i].value)},
+				}), nil
+			}
+
+			if c > 0 {
+				return nestedResponse([]PathError{
+					PathError{Path: elements[i].path, Error: "must not be less than " + elements[i-1].path},
+				}), nil
+			}
+		}
+
+		return Response{IsValid: true}, nil
+	})
+}
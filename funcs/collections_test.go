@@ -0,0 +1,77 @@
+package funcs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nmante/validator/compare"
+)
+
+func TestCollectionFuncs(t *testing.T) {
+	byID := func(v interface{}) interface{} { return v.(map[string]interface{})["id"] }
+
+	collectionTests := []struct {
+		f      Func
+		value  interface{}
+		nested []PathError
+	}{
+		{f: Each(IsInt), value: []interface{}{1, "a", 3, 4.5}, nested: []PathError{
+			PathError{Path: "[1]", Error: "must be a int"},
+			PathError{Path: "[3]", Error: "must be a int"},
+		}},
+		{f: Each(IsLength(1)), value: [2]string{"a", "b"}},
+		{f: EachKey(IsLength(1)), value: map[string]int{"a": 1, "bc": 2}, nested: []PathError{
+			PathError{Path: "[bc]", Error: "Must have length 1"},
+		}},
+		{f: EachValue(IsInt), value: map[string]interface{}{"b": "x", "a": 1}, nested: []PathError{
+			PathError{Path: "[b]", Error: "must be a int"},
+		}},
+		{f: Each(Each(IsInt)), value: [][]interface{}{{1}, {2, "x"}}, nested: []PathError{
+			PathError{Path: "[1][1]", Error: "must be a int"},
+		}},
+		{f: Unique, value: []string{"a", "b", "a"}, nested: []PathError{
+			PathError{Path: "[2]", Error: "must be unique, duplicates [0]"},
+		}},
+		{f: Unique, value: [][]int{{1}, {1}}, nested: []PathError{
+			PathError{Path: "[1]", Error: "must be unique, duplicates [0]"},
+		}},
+		{f: UniqueBy(byID), value: []map[string]interface{}{{"id": 1}, {"id": 2}}},
+		{f: Contains("go"), value: []string{"rust", "go"}},
+		{f: SubsetOf("a", "b"), value: []string{"a", "c"}, nested: []PathError{
+			PathError{Path: "[1]", Error: "must be one of a, b"},
+		}},
+		{f: Sorted(compare.Int), value: []int{1, 3, 2}, nested: []PathError{
+			PathError{Path: "[2]", Error: "must not be less than [1]"},
+		}},
+		{f: Sorted(compare.Int), value: []interface{}{1.0, 2.0}, nested: []PathError{
+			PathError{Path: "[1]", Error: "can't compare float64 values"},
+		}},
+		{f: Sorted(compare.Float64), value: []interface{}{1.0, 2}, nested: []PathError{
+			PathError{Path: "[1]", Error: "must have the same type as [0]"},
+		}},
+		{f: Sorted(compare.Float64), value: []interface{}{1.0, 2.0}},
+		{f: EachValue(IsLength(1)), value: map[int]string{10: "", 2: "", 1: "a"}, nested: []PathError{
+			PathError{Path: "[2]", Error: "Must have length 1"},
+			PathError{Path: "[10]", Error: "Must have length 1"},
+		}},
+	}
+
+	for i, test := range collectionTests {
+		r, err := test.f(test.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if r.IsValid != (len(test.nested) == 0) || !reflect.DeepEqual(r.Nested, test.nested) {
+			t.Errorf("Test %d: nested errors should be %+v. They are %+v", i, test.nested, r.Nested)
+		}
+	}
+
+	if r, _ := Contains("c")([]string{"a"}); r.IsValid {
+		t.Error("Contains should fail when the value is missing")
+	}
+
+	if _, err := Each(IsInt)(1); err != ErrNotCollection {
+		t.Errorf("Each should fail on a value that isn't a collection. Error: %v", err)
+	}
+}
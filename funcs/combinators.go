@@ -0,0 +1,209 @@
+package funcs
+
+import (
+	"reflect"
+	"strings"
+)
+
+// And checks that a value passes every Func, stopping at the first error. A warning or info doesn't
+// stop it, and is returned if no later Func fails with an error
+func And(fns ...Func) Func {
+	return WithMeta(Meta{Name: "And", Children: describeAll(fns...)}, func(v interface{}) (Response, error) {
+		result := Response{IsValid: true}
+		for _, f := range fns {
+			r, err := f(v)
+			if err != nil || !passed(r) {
+				return r, err
+			}
+
+			if result.IsValid && !r.IsValid {
+				result = r
+			}
+		}
+
+		return result, nil
+	})
+}
+
+// Or checks that a value passes left or right
+func Or(left Func, right Func) Func {
+	return AnyOf(left, right)
+}
+
+// AnyOf checks that a value passes at least one Func, stopping at the first that passes. A Func that
+// fails at runtime counts as a failure, so alternatives that only accept some types can be combined.
+// Runtime errors aren't meant for users, so their messages aren't reported. A Func that fails with a
+// warning or info passes, and its failure is returned if no Func passes cleanly
+func AnyOf(fns ...Func) Func {
+	return WithMeta(Meta{Name: "AnyOf", Children: describeAll(fns...)}, func(v interface{}) (Response, error) {
+		messages := []string{}
+		var lenient *Response
+		for _, f := range fns {
+			r, ok := try(f, v)
+			if r.IsValid {
+				return r, nil
+			}
+
+			if passed(r) {
+				if lenient == nil {
+					lenient = &r
+				}
+				continue
+			}
+
+			if ok {
+				messages = append(messages, r.Error)
+			}
+		}
+
+		if lenient != nil {
+			return *lenient, nil
+		}
+
+		return Response{IsValid: false, Error: joinMessages(messages)}, nil
+	})
+}
+
+// ExactlyOne checks that a value passes exactly one Func, stopping at the second that passes. Funcs
+// that fail at runtime count as failures, and Funcs that fail with a warning or info pass, like in
+// AnyOf
+func ExactlyOne(fns ...Func) Func {
+	return WithMeta(Meta{Name: "ExactlyOne", Children: describeAll(fns...)}, func(v interface{}) (Response, error) {
+		messages := []string{}
+		result := Response{IsValid: true}
+		count := 0
+		for _, f := range fns {
+			r, ok := try(f, v)
+			if passed(r) {
+				count++
+				if count > 1 {
+					return Response{IsValid: false, Error: "must match only one condition"}, nil
+				}
+
+				result = r
+				continue
+			}
+
+			if ok {
+				messages = append(messages, r.Error)
+			}
+		}
+
+		if count == 1 {
+			return result, nil
+		}
+
+		return Response{IsValid: false, Error: joinMessages(messages)}, nil
+	})
+}
+
+// Not checks that a value fails f with an error. message is the error when it passes, or only fails
+// with a warning or info
+func Not(f Func, message string) Func {
+	return WithMeta(Meta{Name: "Not", Description: message, Children: describeAll(f)}, func(v interface{}) (Response, error) {
+		r, err := f(v)
+		if err != nil {
+			return Response{}, err
+		}
+
+		if passed(r) {
+			return Response{IsValid: false, Error: message}, nil
+		}
+
+		return Response{IsValid: true}, nil
+	})
+}
+
+// When runs then if predicate is true for the value, otherwise it runs otherwise. Either Func can
+// be nil, which passes
+func When(predicate func(interface{}) bool, then Func, otherwise Func) Func {
+	return WithMeta(Meta{Name: "When", Children: describeAll(then, otherwise)}, func(v interface{}) (Response, error) {
+		f := otherwise
+		if predicate(v) {
+			f = then
+		}
+
+		if f == nil {
+			return Response{IsValid: true}, nil
+		}
+
+		return f(v)
+	})
+}
+
+// Optional passes nil values and nil pointers, and checks any other value with fns. Zero values
+// like 0 and "" are checked
+func Optional(fns ...Func) Func {
+	and := And(fns...)
+
+	return WithMeta(Meta{Name: "Optional", Children: describeAll(fns...)}, func(v interface{}) (Response, error) {
+		if value := reflect.ValueOf(v); v == nil || value.Kind() == reflect.Pointer && value.IsNil() {
+			return Response{IsValid: true}, nil
+		}
+
+		return and(v)
+	})
+}
+
+// WithSeverity returns a Func that behaves like f, but reports its failures with severity. A
+// SeverityWarning or SeverityInfo failure doesn't make the value invalid
+func WithSeverity(severity Severity, f Func) Func {
+	meta, _ := Describe(f)
+	meta.Params = append(append([]Param{}, meta.Params...), Param{Name: "severity", Value: severity.String()})
+
+	return WithMeta(meta, func(v interface{}) (Response, error) {
+		r, err := f(v)
+		if err != nil {
+			return r, err
+		}
+
+		nested := make([]PathError, len(r.Nested))
+		for i, pe := range r.Nested {
+			nested[i] = pe
+			if pe.Severity == SeverityError {
+				nested[i].Severity = severity
+			}
+		}
+
+		if len(nested) > 0 {
+			r.Nested = nested
+		}
+
+		if !r.IsValid {
+			r.Severity = severity
+		}
+
+		return r, nil
+	})
+}
+
+// Warning returns a Func that reports f's failures as warnings
+func Warning(f Func) Func {
+	return WithSeverity(SeverityWarning, f)
+}
+
+// passed checks if a Response doesn't make its value invalid, because it's valid or its failure is
+// only a warning or info
+func passed(r Response) bool {
+	return r.IsValid || r.Severity != SeverityError
+}
+
+// try runs f, treating a runtime error as a failure. ok is false if f failed without a message to
+// show users, which includes runtime errors
+func try(f Func, v interface{}) (Response, bool) {
+	r, err := f(v)
+	if err != nil {
+		return Response{IsValid: false}, false
+	}
+
+	return r, r.IsValid || r.Error != ""
+}
+
+// joinMessages joins the messages of failed alternatives with "or"
+func joinMessages(messages []string) string {
+	if len(messages) == 0 {
+		return "is invalid"
+	}
+
+	return strings.Join(messages, " or ")
+}
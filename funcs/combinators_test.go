@@ -0,0 +1,116 @@
+package funcs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nmante/validator/compare"
+	"github.com/nmante/validator/transform"
+)
+
+func TestCombinators(t *testing.T) {
+	isUUID := func(v interface{}) (Response, error) {
+		s, ok := v.(string)
+		if ok && len(s) == 36 && s[8] == '-' {
+			return Response{IsValid: true}, nil
+		}
+
+		return Response{IsValid: false, Error: "must be a UUID"}, nil
+	}
+
+	isPositive := func(v interface{}) bool {
+		n, ok := v.(int)
+		return ok && n > 0
+	}
+
+	combinatorTests := []struct {
+		f       Func
+		value   interface{}
+		isValid bool
+		message string
+	}{
+		{f: And(IsInt, IsBool), value: 1, isValid: false, message: "must be a bool"},
+		{f: And(String.IsEmail, IsLengthBetween(1, 5)), value: "a@b.com", isValid: false, message: "Must be between length 1 and 5"},
+		{f: Or(isUUID, String.IsInt), value: "42", isValid: true},
+		{f: Or(isUUID, String.IsInt), value: "123e4567-e89b-12d3-a456-426614174000", isValid: true},
+		{f: Or(isUUID, IsInt), value: "abc", isValid: false, message: "must be a UUID or must be a int"},
+		{f: AnyOf(IsInt, IsBool, IsFloat64), value: 1.5, isValid: true},
+		{f: ExactlyOne(IsInt, IsLength(1)), value: "a", isValid: true},
+		{f: ExactlyOne(String.IsInt, IsLength(1)), value: "1", isValid: false, message: "must match only one condition"},
+		{f: ExactlyOne(IsInt, IsBool), value: "x", isValid: false, message: "must be a int or must be a bool"},
+		{f: Not(String.IsEmail, "must not be an email"), value: "a@b.com", isValid: false, message: "must not be an email"},
+		{f: Not(String.IsEmail, "must not be an email"), value: "ab", isValid: true},
+		{f: When(isPositive, IsLength(1), nil), value: -1, isValid: true},
+		{f: When(isPositive, nil, IsBool), value: -1, isValid: false, message: "must be a bool"},
+		{f: Optional(String.IsEmail), value: "", isValid: false, message: "Must be an email address"},
+		{f: Optional(String.IsEmail), value: nil, isValid: true},
+		{f: Optional(String.IsEmail), value: (*string)(nil), isValid: true},
+		{f: Optional(IsBetween(transform.None, compare.Int, 5, 10)), value: 0, isValid: false, message: "must be between 5 and 10"},
+		{f: AnyOf(IsBetween(transform.StringToInt, compare.Int, 1, 5), IsBool), value: "abc", isValid: false, message: "must be a bool"},
+		{f: ExactlyOne(IsBetween(transform.StringToInt, compare.Int, 1, 5)), value: "abc", isValid: false, message: "is invalid"},
+		{f: Optional(String.IsEmail), value: "hello", isValid: false, message: "Must be an email address"},
+	}
+
+	for i, test := range combinatorTests {
+		r, err := test.f(test.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if r.IsValid != test.isValid || r.Error != test.message {
+			t.Errorf("Test %d: response should be {%t %q}. It is {%t %q}", i, test.isValid, test.message, r.IsValid, r.Error)
+		}
+	}
+
+	if _, err := Not(String.IsEmail, "")(1); err == nil {
+		t.Error("Not should return runtime errors")
+	}
+}
+
+func TestWithSeverity(t *testing.T) {
+	r, _ := Warning(IsInt)("a")
+	if r.IsValid || r.Severity != SeverityWarning {
+		t.Errorf("Response should be an invalid warning. It is %+v", r)
+	}
+
+	if name, _ := NameOf(Warning(IsInt)); name != "IsInt" {
+		t.Errorf("Name should be IsInt. It is %q", name)
+	}
+
+	r, _ = Each(WithSeverity(SeverityInfo, IsInt))([]interface{}{1, "a"})
+	expected := []PathError{PathError{Path: "[1]", Error: "must be a int", Severity: SeverityInfo}}
+	if !r.IsValid || !reflect.DeepEqual(r.Nested, expected) {
+		t.Errorf("Response should be valid with nested infos %+v. It is %+v", expected, r)
+	}
+}
+
+func TestCombinatorSeverity(t *testing.T) {
+	severityTests := []struct {
+		f        Func
+		value    interface{}
+		isValid  bool
+		severity Severity
+		message  string
+	}{
+		{f: And(Warning(IsInt), IsLength(2)), value: "a", isValid: false, severity: SeverityError, message: "Must have length 2"},
+		{f: And(Warning(IsInt), IsLength(1)), value: "a", isValid: false, severity: SeverityWarning, message: "must be a int"},
+		{f: And(IsLength(1), Warning(IsInt), WithSeverity(SeverityInfo, IsBool)), value: "a", isValid: false, severity: SeverityWarning, message: "must be a int"},
+		{f: Not(Warning(IsInt), "must not be a int"), value: "a", isValid: false, severity: SeverityError, message: "must not be a int"},
+		{f: Not(IsInt, "must not be a int"), value: "a", isValid: true},
+		{f: AnyOf(Warning(IsInt), IsBool), value: "a", isValid: false, severity: SeverityWarning, message: "must be a int"},
+		{f: AnyOf(Warning(IsInt), IsLength(1)), value: "a", isValid: true},
+		{f: ExactlyOne(Warning(IsInt), IsBool), value: "a", isValid: false, severity: SeverityWarning, message: "must be a int"},
+		{f: ExactlyOne(Warning(IsInt), IsLength(1)), value: "a", isValid: false, severity: SeverityError, message: "must match only one condition"},
+	}
+
+	for i, test := range severityTests {
+		r, err := test.f(test.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if r.IsValid != test.isValid || r.Severity != test.severity || r.Error != test.message {
+			t.Errorf("Test %d: response should be {%t %s %q}. It is {%t %s %q}", i, test.isValid, test.severity, test.message, r.IsValid, r.Severity, r.Error)
+		}
+	}
+}
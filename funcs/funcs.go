@@ -21,11 +21,52 @@ var (
 	}
 )
 
+// Severity is how serious a failed check is. Only errors make a value invalid; warnings and infos
+// are reported alongside them
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+	SeverityInfo
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "info"
+	}
+
+	return "error"
+}
+
 // Response contains info around if a validator function was valid. If it isn't valid, an
-// error message is also returned
+// error message is also returned. Funcs that check the parts of a value, like the elements of a
+// slice, can report which parts failed in Nested. Severity is how serious the failure is
 type Response struct {
-	IsValid bool
-	Error   string
+	IsValid  bool
+	Error    string
+	Nested   []PathError
+	Severity Severity
+}
+
+// PathError is an error message for part of a value. Path is relative to the value, like "[2]" for
+// an element or "city" for a field. A SeverityError PathError takes the severity of its Response
+type PathError struct {
+	Path     string
+	Error    string
+	Severity Severity
+}
+
+// SeverityOf returns the severity of a nested error in r
+func (r Response) SeverityOf(pe PathError) Severity {
+	if pe.Severity == SeverityError {
+		return r.Severity
+	}
+
+	return pe.Severity
 }
 
 type Interface interface {
@@ -35,28 +76,30 @@ type Interface interface {
 // Func is function type that all validator functions must follow
 type Func func(interface{}) (Response, error)
 
-// IsTransformableTo checks if a value of type 'A' is transformable to type 'B'
+// IsTransformableTo checks if a value of type 'A' is transformable to type 'B'. Values the
+// transformer can't transform aren't valid
 func IsTransformableTo(transformer transform.Interface, _type reflect.Type) Func {
-	return func(v interface{}) (Response, error) {
+	return WithMeta(typeMeta("IsTransformableTo", _type), func(v interface{}) (Response, error) {
 		t, err := transformer.Transform(v)
-		if err != nil {
-			return Response{}, err
-		}
-
-		if reflect.TypeOf(t) != _type {
+		if err != nil || reflect.TypeOf(t) != _type {
 			return Response{
 				IsValid: false,
-				Error:   fmt.Sprintf("%v not transformable to %s", v, _type.Name()),
+				Error:   fmt.Sprintf("%v not transformable to %s", v, _type),
 			}, nil
 		}
 
 		return Response{IsValid: true, Error: ""}, nil
-	}
+	})
 }
 
 // IsEqual transforms a 'v' to a type, and checks if it's equal to 'right'
 func IsEqual(transformer transform.Interface, comparer compare.Interface, right interface{}) Func {
-	return func(v interface{}) (Response, error) {
+	return WithMeta(Meta{
+		Name:        "IsEqual",
+		Description: fmt.Sprintf("must be equal to %v", right),
+		Params:      []Param{Param{Name: "value", Value: right}},
+		ErrorCodes:  []string{"equal"},
+	}, func(v interface{}) (Response, error) {
 		value, err := transformer.Transform(v)
 		if err != nil {
 			return Response{}, err
@@ -71,12 +114,17 @@ func IsEqual(transformer transform.Interface, comparer compare.Interface, right
 		}
 
 		return Response{IsValid: false, Error: fmt.Sprintf("must be equal to %d", right)}, nil
-	}
+	})
 }
 
-// IsBetween checks if a value is between a lower and an upper value
+// IsBetween checks if a value is between a lower and an upper value, inclusive
 func IsBetween(transformer transform.Interface, comparer compare.Interface, lower interface{}, upper interface{}) Func {
-	return func(v interface{}) (Response, error) {
+	return WithMeta(Meta{
+		Name:        "IsBetween",
+		Description: fmt.Sprintf("must be between %v and %v", lower, upper),
+		Params:      []Param{Param{Name: "lower", Value: lower}, Param{Name: "upper", Value: upper}},
+		ErrorCodes:  []string{"between"},
+	}, func(v interface{}) (Response, error) {
 		value, err := transformer.Transform(v)
 		if err != nil {
 			return Response{}, err
@@ -86,17 +134,22 @@ func IsBetween(transformer transform.Interface, comparer compare.Interface, lowe
 			return Response{}, err
 		}
 
-		if comparer.Compare(lower, value) < 1 && comparer.Compare(value, upper) > -1 {
+		if comparer.Compare(value, lower) >= 0 && comparer.Compare(value, upper) <= 0 {
 			return Response{IsValid: true, Error: ""}, nil
 		}
 
 		return Response{IsValid: false, Error: fmt.Sprintf("must be between %d and %d", lower, upper)}, nil
-	}
+	})
 }
 
 // IsLength checks if the length of an item equals a value
 func IsLength(length int) Func {
-	return func(v interface{}) (Response, error) {
+	return WithMeta(Meta{
+		Name:        "IsLength",
+		Description: fmt.Sprintf("must have length %d", length),
+		Params:      []Param{Param{Name: "length", Value: length}},
+		ErrorCodes:  []string{"length"},
+	}, func(v interface{}) (Response, error) {
 		value := reflect.ValueOf(v)
 		if _, ok := validKinds[value.Kind()]; !ok {
 			return Response{}, ErrInvalidKind
@@ -110,12 +163,17 @@ func IsLength(length int) Func {
 			IsValid: false,
 			Error:   fmt.Sprintf("Must have length %d", length),
 		}, nil
-	}
+	})
 }
 
 // IsLengthBetween checks if the length of an item is within a range
 func IsLengthBetween(lower int, upper int) Func {
-	return func(v interface{}) (Response, error) {
+	return WithMeta(Meta{
+		Name:        "IsLengthBetween",
+		Description: fmt.Sprintf("must have a length between %d and %d", lower, upper),
+		Params:      []Param{Param{Name: "lower", Value: lower}, Param{Name: "upper", Value: upper}},
+		ErrorCodes:  []string{"length"},
+	}, func(v interface{}) (Response, error) {
 		value := reflect.ValueOf(v)
 		if _, ok := validKinds[value.Kind()]; !ok {
 			return Response{}, ErrInvalidKind
@@ -129,7 +187,7 @@ func IsLengthBetween(lower int, upper int) Func {
 			IsValid: false,
 			Error:   fmt.Sprintf("Must be between length %d and %d", lower, upper),
 		}, nil
-	}
+	})
 }
 
 // isTypesEqual checks if a list of values all have the same type
@@ -149,13 +207,13 @@ func isTypesEqual(values ...interface{}) (bool, error) {
 
 // IsType checks if a value is of a certain type
 func IsType(_type reflect.Type) Func {
-	return func(v interface{}) (Response, error) {
+	return WithMeta(typeMeta("IsType", _type), func(v interface{}) (Response, error) {
 		if reflect.TypeOf(v) != _type {
-			return Response{IsValid: false, Error: fmt.Sprintf("must be a %s", _type.Name())}, nil
+			return Response{IsValid: false, Error: fmt.Sprintf("must be a %s", _type)}, nil
 		}
 
 		return Response{IsValid: true, Error: ""}, nil
-	}
+	})
 }
 
 // IsBool checks if a value is a boolean
@@ -39,6 +39,13 @@ func TestIsTransformableToInt(t *testing.T) {
 	if !r.IsValid {
 		t.Error(r.Error)
 	}
+
+	for _, v := range []interface{}{"abc", 53} {
+		r, err = IsTransformableTo(transform.StringToInt, types.Int)(v)
+		if err != nil || r.IsValid {
+			t.Errorf("%v should be invalid without an error. Response is %+v, error is %v", v, r, err)
+		}
+	}
 }
 
 func TestIsEmail(t *testing.T) {
@@ -112,24 +119,26 @@ func TestIsEqual(t *testing.T) {
 }
 
 func TestIsBetween(t *testing.T) {
-	r, err := IsBetween(transform.StringToInt, compare.Int, 1, 100)("53")
-
-	if err != nil {
-		t.Error(err)
-	}
-
-	if r.IsValid {
-		t.Error(r.Error)
-	}
-
-	r, err = IsBetween(transform.StringToInt, compare.Int, 1, 100)("101")
-
-	if err != nil {
-		t.Error(err)
-	}
+	betweenTests := []struct {
+		value   string
+		isValid bool
+	}{
+		{value: "53", isValid: true},
+		{value: "1", isValid: true},
+		{value: "100", isValid: true},
+		{value: "0", isValid: false},
+		{value: "101", isValid: false},
+	}
+
+	for _, test := range betweenTests {
+		r, err := IsBetween(transform.StringToInt, compare.Int, 1, 100)(test.value)
+		if err != nil {
+			t.Error(err)
+		}
 
-	if !r.IsValid {
-		t.Error(r.Error)
+		if r.IsValid != test.isValid {
+			t.Errorf("%s: IsValid should be %t. Response is %+v", test.value, test.isValid, r)
+		}
 	}
 }
 
@@ -163,3 +172,31 @@ func BenchmarkNormalIsBetween(b *testing.B) {
 		_, _ = isBetween("101", 1, 100)
 	}
 }
+
+func TestNameOf(t *testing.T) {
+	nameTests := []struct {
+		f    Func
+		name string
+	}{
+		{f: IsInt, name: "IsInt"},
+		{f: String.IsEmail, name: "String.IsEmail"},
+		{f: IsBetween(transform.StringToInt, compare.Int, 1, 100), name: "IsBetween"},
+		{f: IsLength(3), name: "IsLength"},
+	}
+
+	for _, test := range nameTests {
+		if name, ok := NameOf(test.f); !ok || name != test.name {
+			t.Errorf("Func should be named %s. It is named %s", test.name, name)
+		}
+	}
+
+	custom := func(v interface{}) (Response, error) { return Response{IsValid: true}, nil }
+	if _, ok := NameOf(custom); ok {
+		t.Error("Unregistered funcs should not have a name")
+	}
+
+	Register("custom", custom)
+	if name, _ := NameOf(custom); name != "custom" {
+		t.Errorf("Func should be named custom. It is named %s", name)
+	}
+}
@@ -0,0 +1,184 @@
+package funcs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Meta describes a Func, for documentation and introspection. The built in constructors describe
+// the Funcs they return, and Funcs can be described with WithMeta
+type Meta struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Params      []Param       `json:"params,omitempty"`
+	Examples    []interface{} `json:"examples,omitempty"`
+	// ErrorCodes identify the kinds of failure the Func reports, like "between" or "type"
+	ErrorCodes []string `json:"error_codes,omitempty"`
+	// Children describe the Funcs run by a combinator, like And or Each
+	Children []Meta `json:"children,omitempty"`
+	// Schema describes a nested validator, like the one passed to validator.Nested
+	Schema interface{} `json:"schema,omitempty"`
+}
+
+// Param is a value a Func was built with, like the bounds of IsBetween
+type Param struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+// Param returns the value of a named param
+func (m Meta) Param(name string) (interface{}, bool) {
+	for _, p := range m.Params {
+		if p.Name == name {
+			return p.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// Bounds returns the inclusive range of values a Func allows, if it's described as IsBetween,
+// Between, Min, Max or IsEqual. A bound is nil when the range isn't limited on that side
+func Bounds(meta Meta) (lower interface{}, upper interface{}, ok bool) {
+	switch meta.Name {
+	case "IsBetween", "Between":
+		lower, _ = meta.Param("lower")
+		upper, _ = meta.Param("upper")
+	case "Min":
+		lower, _ = meta.Param("min")
+	case "Max":
+		upper, _ = meta.Param("max")
+	case "IsEqual":
+		lower, _ = meta.Param("value")
+		upper = lower
+	default:
+		return nil, nil, false
+	}
+
+	return lower, upper, true
+}
+
+// LengthBounds returns the inclusive range of lengths a Func allows, if it's described as IsLength
+// or IsLengthBetween
+func LengthBounds(meta Meta) (lower interface{}, upper interface{}, ok bool) {
+	switch meta.Name {
+	case "IsLength":
+		lower, _ = meta.Param("length")
+		upper = lower
+	case "IsLengthBetween":
+		lower, _ = meta.Param("lower")
+		upper, _ = meta.Param("upper")
+	default:
+		return nil, nil, false
+	}
+
+	return lower, upper, true
+}
+
+// CheckedType returns the name of the type a Func checks for, like "int" for IsInt or an Untyped
+// TypedFunc[int]. For checks on strings that hold another type, like String.IsInt, it's the held
+// type
+func CheckedType(meta Meta) (string, bool) {
+	_type, ok := meta.Param("type")
+	if !ok {
+		return "", false
+	}
+
+	for _, code := range meta.ErrorCodes {
+		if code == "type" {
+			return fmt.Sprint(_type), true
+		}
+	}
+
+	return "", false
+}
+
+// metaProbe is passed to Funcs returned from WithMetaPathFunc to ask for their Meta. It's only
+// passed to Funcs whose code is describedPointer, so other Funcs are never called with it. path
+// holds the values being described around the Func
+type metaProbe struct {
+	meta Meta
+	path []interface{}
+}
+
+// WithMeta returns a Func that behaves like f and is described by meta
+func WithMeta(meta Meta, f Func) Func {
+	return WithMetaFunc(func() Meta { return meta }, f)
+}
+
+// WithMetaFunc returns a Func that behaves like f and is described by the Meta that meta returns.
+// meta is called each time the Func is described, so it can describe things that change, like a
+// validator that rules are added to
+func WithMetaFunc(meta func() Meta, f Func) Func {
+	return WithMetaPathFunc(func(path []interface{}) Meta { return meta() }, f)
+}
+
+// WithMetaPathFunc is like WithMetaFunc, but meta is passed the values being described around the
+// Func, outermost first, like the validators it's nested in. A Func that describes a value that's
+// already on the path, like a validator nested in itself, can describe a reference to it instead
+//
+//go:noinline
+func WithMetaPathFunc(meta func(path []interface{}) Meta, f Func) Func {
+	return func(v interface{}) (Response, error) {
+		if p, ok := v.(*metaProbe); ok {
+			p.meta = meta(p.path)
+			return Response{}, nil
+		}
+
+		return f(v)
+	}
+}
+
+// Describe returns the Meta of a Func returned by WithMeta or Named, or of a registered Func
+func Describe(f Func) (Meta, bool) {
+	return DescribePath(nil, f)
+}
+
+// DescribePath is like Describe for a Func inside the values on path, which are passed to the meta
+// of a WithMetaPathFunc Func. Only Funcs returned from WithMetaPathFunc are called to describe them,
+// other Funcs are looked up in the registry
+func DescribePath(path []interface{}, f Func) (Meta, bool) {
+	if f == nil {
+		return Meta{}, false
+	}
+
+	pointer := reflect.ValueOf(f).Pointer()
+	if pointer == describedPointer {
+		p := &metaProbe{path: path}
+		f(p)
+		return p.meta, true
+	}
+
+	metasMu.RLock()
+	defer metasMu.RUnlock()
+
+	meta, ok := metas[pointer]
+	return meta, ok
+}
+
+// describeAll describes Funcs, like the children of a combinator. Funcs without a Meta are
+// described by an empty one
+func describeAll(fns ...Func) []Meta {
+	described := []Meta{}
+	for _, f := range fns {
+		if f == nil {
+			continue
+		}
+
+		meta, _ := Describe(f)
+		described = append(described, meta)
+	}
+
+	return described
+}
+
+// typeMeta describes a Func that checks the type of a value. Types are named like in Go source, so
+// unnamed types like []string are described too
+func typeMeta(name string, _type reflect.Type) Meta {
+	return Meta{
+		Name:        name,
+		Description: fmt.Sprintf("must be a %s", _type),
+		Params:      []Param{Param{Name: "type", Value: _type.String()}},
+		ErrorCodes:  []string{"type"},
+	}
+}
@@ -0,0 +1,157 @@
+package funcs
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/nmante/validator/compare"
+	"github.com/nmante/validator/transform"
+)
+
+func TestDescribe(t *testing.T) {
+	between := Meta{
+		Name:        "IsBetween",
+		Description: "must be between 1 and 10",
+		Params:      []Param{Param{Name: "lower", Value: 1}, Param{Name: "upper", Value: 10}},
+		ErrorCodes:  []string{"between"},
+	}
+	isInt := Meta{
+		Name:        "IsInt",
+		Description: "must be a int",
+		Params:      []Param{Param{Name: "type", Value: "int"}},
+		ErrorCodes:  []string{"type"},
+	}
+
+	describeTests := []struct {
+		f        Func
+		expected Meta
+	}{
+		{f: IsBetween(transform.None, compare.Int, 1, 10), expected: between},
+		{f: IsInt, expected: isInt},
+		{f: And(IsInt, IsBetween(transform.None, compare.Int, 1, 10)), expected: Meta{Name: "And", Children: []Meta{isInt, between}}},
+		{f: Each(IsInt), expected: Meta{Name: "Each", Children: []Meta{isInt}}},
+		{f: Named("isEven", IsInt), expected: Meta{Name: "isEven"}},
+		{f: WithMeta(Meta{Name: "IsUUID", Examples: []interface{}{"123e4567-e89b-12d3-a456-426614174000"}}, IsInt), expected: Meta{Name: "IsUUID", Examples: []interface{}{"123e4567-e89b-12d3-a456-426614174000"}}},
+		{f: String.IsEqualToInt(5), expected: Meta{Name: "String.IsEqualToInt", Description: "must contain an integer equal to 5", Params: []Param{Param{Name: "value", Value: 5}}, ErrorCodes: []string{"equal"}}},
+		{f: IsType(reflect.TypeOf([]string{})), expected: Meta{Name: "IsType", Description: "must be a []string", Params: []Param{Param{Name: "type", Value: "[]string"}}, ErrorCodes: []string{"type"}}},
+		{f: IsType(reflect.TypeOf(map[string]int{})), expected: Meta{Name: "IsType", Description: "must be a map[string]int", Params: []Param{Param{Name: "type", Value: "map[string]int"}}, ErrorCodes: []string{"type"}}},
+		{f: Untyped(Between(1, 2)), expected: untyped("int", Meta{Name: "Between", Description: "must be between 1 and 2", Params: []Param{Param{Name: "lower", Value: 1}, Param{Name: "upper", Value: 2}}, ErrorCodes: []string{"between"}})},
+		{f: Untyped(Min(int64(0))), expected: untyped("int64", Meta{Name: "Min", Description: "must be at least 0", Params: []Param{Param{Name: "min", Value: int64(0)}}, ErrorCodes: []string{"min"}})},
+		{f: Untyped(Max(2.5)), expected: untyped("float64", Meta{Name: "Max", Description: "must be at most 2.5", Params: []Param{Param{Name: "max", Value: 2.5}}, ErrorCodes: []string{"max"}})},
+		{f: Untyped(OneOf("a", "b")), expected: untyped("string", Meta{Name: "OneOf", Description: "must be one of a, b", Params: []Param{Param{Name: "values", Value: []interface{}{"a", "b"}}}, ErrorCodes: []string{"one_of"}})},
+		{f: Untyped(Convert(strconv.Atoi, Max(9))), expected: untyped("string", Meta{
+			Name:        "Convert",
+			Description: "must be convertible to int",
+			Params:      []Param{Param{Name: "to", Value: "int"}},
+			ErrorCodes:  []string{"convert"},
+			Children:    []Meta{Meta{Name: "Max", Description: "must be at most 9", Params: []Param{Param{Name: "max", Value: 9}}, ErrorCodes: []string{"max"}}},
+		})},
+		{f: Untyped(Typed[int](IsInt)), expected: untyped("int", isInt)},
+		{f: Untyped(NewTypedFunc(func(v int) (Response, error) { return Response{IsValid: true}, nil })), expected: untyped("int")},
+	}
+
+	for i, test := range describeTests {
+		meta, ok := Describe(test.f)
+		if !ok || !reflect.DeepEqual(meta, test.expected) {
+			t.Errorf("Test %d: meta should be %+v. It is %+v", i, test.expected, meta)
+		}
+	}
+
+	if _, ok := Describe(func(v interface{}) (Response, error) { return Response{}, nil }); ok {
+		t.Error("Func literals should not be described")
+	}
+
+	if lower, _ := between.Param("lower"); lower != 1 {
+		t.Errorf("lower should be 1. It is %v", lower)
+	}
+}
+
+// untyped is the Meta of Untyped for a type, with the Meta of its TypedFunc
+func untyped(_type string, children ...Meta) Meta {
+	meta := Meta{Name: "Untyped", Description: "must be a " + _type, Params: []Param{Param{Name: "type", Value: _type}}, ErrorCodes: []string{"type"}}
+	if len(children) > 0 {
+		meta.Children = children
+	}
+
+	return meta
+}
+
+func TestDescribeDoesNotCallFuncs(t *testing.T) {
+	panics := func(v interface{}) (Response, error) {
+		panic("a Func should not be called to describe it")
+	}
+	onlyStrings := func(v interface{}) (Response, error) {
+		_ = v.(string)
+		return Response{IsValid: true}, nil
+	}
+	RegisterMeta(Meta{Name: "onlyStrings"}, onlyStrings)
+
+	describeTests := []struct {
+		f        Func
+		expected Meta
+		ok       bool
+	}{
+		{f: panics},
+		{f: onlyStrings, expected: Meta{Name: "onlyStrings"}, ok: true},
+		{f: Named("panics", panics), expected: Meta{Name: "panics"}, ok: true},
+		{f: And(panics, Named("onlyStrings", onlyStrings)), expected: Meta{Name: "And", Children: []Meta{Meta{}, Meta{Name: "onlyStrings"}}}, ok: true},
+	}
+
+	for i, test := range describeTests {
+		meta, ok := Describe(test.f)
+		if ok != test.ok || !reflect.DeepEqual(meta, test.expected) {
+			t.Errorf("Test %d: meta should be %+v. It is %+v", i, test.expected, meta)
+		}
+	}
+}
+
+func TestBounds(t *testing.T) {
+	describe := func(f Func) Meta {
+		meta, _ := Describe(f)
+		return meta
+	}
+
+	boundsTests := []struct {
+		meta   Meta
+		bounds func(Meta) (interface{}, interface{}, bool)
+		lower  interface{}
+		upper  interface{}
+		ok     bool
+	}{
+		{meta: describe(IsBetween(transform.None, compare.Int, 1, 10)), bounds: Bounds, lower: 1, upper: 10, ok: true},
+		{meta: describe(IsEqual(transform.None, compare.Int, 5)), bounds: Bounds, lower: 5, upper: 5, ok: true},
+		{meta: describe(Untyped(Min(2.5))).Children[0], bounds: Bounds, lower: 2.5, ok: true},
+		{meta: describe(Untyped(Max(9))).Children[0], bounds: Bounds, upper: 9, ok: true},
+		{meta: describe(String.IsEqualToInt(5)), bounds: Bounds},
+		{meta: describe(IsLength(3)), bounds: Bounds},
+		{meta: describe(IsLength(3)), bounds: LengthBounds, lower: 3, upper: 3, ok: true},
+		{meta: describe(IsLengthBetween(1, 4)), bounds: LengthBounds, lower: 1, upper: 4, ok: true},
+		{meta: describe(IsInt), bounds: LengthBounds},
+	}
+
+	for _, test := range boundsTests {
+		lower, upper, ok := test.bounds(test.meta)
+		if lower != test.lower || upper != test.upper || ok != test.ok {
+			t.Errorf("%s bounds should be %v, %v, %t. They are %v, %v, %t", test.meta.Name, test.lower, test.upper, test.ok, lower, upper, ok)
+		}
+	}
+
+	typeTests := []struct {
+		meta  Meta
+		_type string
+		ok    bool
+	}{
+		{meta: describe(IsInt), _type: "int", ok: true},
+		{meta: describe(Untyped(Min(2.5))), _type: "float64", ok: true},
+		{meta: describe(IsType(reflect.TypeOf([]string{}))), _type: "[]string", ok: true},
+		{meta: Meta{Name: "Custom", Params: []Param{Param{Name: "type", Value: "int"}}}},
+		{meta: describe(IsLength(3))},
+	}
+
+	for _, test := range typeTests {
+		if _type, ok := CheckedType(test.meta); _type != test._type || ok != test.ok {
+			t.Errorf("%s should check for %q, %t. It checks for %q, %t", test.meta.Name, test._type, test.ok, _type, ok)
+		}
+	}
+}
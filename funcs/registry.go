@@ -0,0 +1,70 @@
+package funcs
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/nmante/validator/types"
+)
+
+var (
+	metasMu sync.RWMutex
+	metas   = map[uintptr]Meta{}
+
+	// describedPointer is the code pointer shared by every Func returned from WithMetaPathFunc
+	describedPointer = reflect.ValueOf(WithMetaPathFunc(nil, nil)).Pointer()
+)
+
+func init() {
+	RegisterMeta(typeMeta("IsBool", types.Bool), IsBool)
+	RegisterMeta(typeMeta("IsInt", types.Int), IsInt)
+	RegisterMeta(typeMeta("IsInt8", types.Int8), IsInt8)
+	RegisterMeta(typeMeta("IsInt16", types.Int16), IsInt16)
+	RegisterMeta(typeMeta("IsInt32", types.Int32), IsInt32)
+	RegisterMeta(typeMeta("IsInt64", types.Int64), IsInt64)
+	RegisterMeta(typeMeta("IsUint", types.Uint), IsUint)
+	RegisterMeta(typeMeta("IsUint8", types.Uint8), IsUint8)
+	RegisterMeta(typeMeta("IsUint16", types.Uint16), IsUint16)
+	RegisterMeta(typeMeta("IsUint32", types.Uint32), IsUint32)
+	RegisterMeta(typeMeta("IsUint64", types.Uint64), IsUint64)
+	RegisterMeta(typeMeta("IsUintptr", types.Uintptr), IsUintptr)
+	RegisterMeta(typeMeta("IsByte", types.Byte), IsByte)
+	RegisterMeta(typeMeta("IsRune", types.Rune), IsRune)
+	RegisterMeta(typeMeta("IsFloat32", types.Float32), IsFloat32)
+	RegisterMeta(typeMeta("IsFloat64", types.Float64), IsFloat64)
+	RegisterMeta(typeMeta("IsComplex64", types.Complex64), IsComplex64)
+	RegisterMeta(typeMeta("IsComplex128", types.Complex128), IsComplex128)
+	RegisterMeta(Meta{Name: "Unique", Description: "must have unique elements", ErrorCodes: []string{"unique"}}, Unique)
+	RegisterMeta(Meta{Name: "String.IsEmail", Description: "must be an email address", ErrorCodes: []string{"email"}}, String.IsEmail)
+	RegisterMeta(typeMeta("String.IsInt", types.Int), String.IsInt)
+	RegisterMeta(typeMeta("String.IsFloat32", types.Float32), String.IsFloat32)
+	RegisterMeta(typeMeta("String.IsFloat64", types.Float64), String.IsFloat64)
+	RegisterMeta(typeMeta("String.IsBool", types.Bool), String.IsBool)
+	RegisterMeta(typeMeta("String.IsUint", types.Uint), String.IsUint)
+}
+
+// Named returns a Func that behaves like f and reports name from NameOf. Use it for Funcs built
+// by constructors or func literals, where every Func shares the same code
+func Named(name string, f Func) Func {
+	return WithMeta(Meta{Name: name}, f)
+}
+
+// Register names a top level Func or method value so it can be identified by NameOf. Funcs are
+// identified by their code, so Funcs returned by a constructor should be wrapped with Named instead
+func Register(name string, f Func) {
+	RegisterMeta(Meta{Name: name}, f)
+}
+
+// RegisterMeta describes a top level Func or method value, like Register
+func RegisterMeta(meta Meta, f Func) {
+	metasMu.Lock()
+	defer metasMu.Unlock()
+
+	metas[reflect.ValueOf(f).Pointer()] = meta
+}
+
+// NameOf returns the name a Func was registered, described or wrapped with
+func NameOf(f Func) (string, bool) {
+	meta, ok := Describe(f)
+	return meta.Name, ok
+}
@@ -17,9 +17,15 @@ var (
 
 type _string struct{}
 
-// IsInRangeInts checks if a string value is between integers
+// IsInRangeInts checks if a string value is between integers. It's described as String.IsInRangeInts
+// rather than IsBetween, since its bounds don't apply to the string itself
 func (s _string) IsInRangeInts(lower int, upper int) Func {
-	return IsBetween(transform.StringToInt, compare.Int, lower, upper)
+	return WithMeta(Meta{
+		Name:        "String.IsInRangeInts",
+		Description: fmt.Sprintf("must contain an integer between %d and %d", lower, upper),
+		Params:      []Param{Param{Name: "lower", Value: lower}, Param{Name: "upper", Value: upper}},
+		ErrorCodes:  []string{"between"},
+	}, IsBetween(transform.StringToInt, compare.Int, lower, upper))
 }
 
 type IsStringBetweenInts struct {
@@ -58,9 +64,15 @@ func (s _string) IsEmail(v interface{}) (Response, error) {
 	return Response{IsValid: false, Error: "Must be an email address"}, nil
 }
 
-// IsEqualToInt checks if the value within a string is equal to an integer
+// IsEqualToInt checks if the value within a string is equal to an integer. It's described as
+// String.IsEqualToInt rather than IsEqual, since its value doesn't apply to the string itself
 func (s _string) IsEqualToInt(right int) Func {
-	return IsEqual(transform.StringToInt, compare.Int, right)
+	return WithMeta(Meta{
+		Name:        "String.IsEqualToInt",
+		Description: fmt.Sprintf("must contain an integer equal to %d", right),
+		Params:      []Param{Param{Name: "value", Value: right}},
+		ErrorCodes:  []string{"equal"},
+	}, IsEqual(transform.StringToInt, compare.Int, right))
 }
 
 // IsStringInt checks if the value within a string is an integer
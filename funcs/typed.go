@@ -0,0 +1,193 @@
+package funcs
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+)
+
+// TypedFunc is a check for values of a known type, so the compiler checks the values and bounds
+// it's built with. It carries its Meta, since it can't be probed like a Func. Use Untyped to add it
+// to a Rule
+type TypedFunc[T any] struct {
+	check func(T) (Response, error)
+	meta  *Meta
+}
+
+// NewTypedFunc returns a TypedFunc that runs check
+func NewTypedFunc[T any](check func(T) (Response, error)) TypedFunc[T] {
+	return TypedFunc[T]{check: check}
+}
+
+// WithTypedMeta returns a TypedFunc that runs check and is described by meta, like WithMeta.
+// Untyped and Convert take over the Meta of the TypedFuncs they're built with
+func WithTypedMeta[T any](meta Meta, check func(T) (Response, error)) TypedFunc[T] {
+	return TypedFunc[T]{check: check, meta: &meta}
+}
+
+// Check runs the TypedFunc on a value
+func (f TypedFunc[T]) Check(v T) (Response, error) {
+	return f.check(v)
+}
+
+// DescribeTyped returns the Meta of a TypedFunc returned by WithTypedMeta, like Describe
+func DescribeTyped[T any](f TypedFunc[T]) (Meta, bool) {
+	if f.meta == nil {
+		return Meta{}, false
+	}
+
+	return *f.meta, true
+}
+
+// Untyped adapts a TypedFunc to a Func. Values that aren't a T are invalid. The Func is described by
+// its type, with the TypedFunc's Meta as its child if it has one
+func Untyped[T any](f TypedFunc[T]) Func {
+	var zero T
+	meta := Meta{
+		Name:        "Untyped",
+		Description: fmt.Sprintf("must be a %T", zero),
+		Params:      []Param{Param{Name: "type", Value: fmt.Sprintf("%T", zero)}},
+		ErrorCodes:  []string{"type"},
+	}
+
+	if child, ok := DescribeTyped(f); ok {
+		meta.Children = []Meta{child}
+	}
+
+	return WithMeta(meta, func(v interface{}) (Response, error) {
+		value, ok := v.(T)
+		if !ok {
+			return Response{IsValid: false, Error: meta.Description}, nil
+		}
+
+		return f.Check(value)
+	})
+}
+
+// Typed adapts a Func to a TypedFunc, described like f
+func Typed[T any](f Func) TypedFunc[T] {
+	typed := func(v T) (Response, error) {
+		return f(v)
+	}
+
+	if meta, ok := Describe(f); ok {
+		return WithTypedMeta(meta, typed)
+	}
+
+	return NewTypedFunc(typed)
+}
+
+// Convert converts a value with convert, then runs fns on the result. Values that can't be
+// converted are invalid
+func Convert[S any, T any](convert func(S) (T, error), fns ...TypedFunc[T]) TypedFunc[S] {
+	var zero T
+	children := []Meta{}
+	for _, f := range fns {
+		child, _ := DescribeTyped(f)
+		children = append(children, child)
+	}
+
+	meta := Meta{
+		Name:        "Convert",
+		Description: fmt.Sprintf("must be convertible to %T", zero),
+		Params:      []Param{Param{Name: "to", Value: fmt.Sprintf("%T", zero)}},
+		ErrorCodes:  []string{"convert"},
+		Children:    children,
+	}
+
+	return WithTypedMeta(meta, func(v S) (Response, error) {
+		converted, err := convert(v)
+		if err != nil {
+			return Response{IsValid: false, Error: fmt.Sprintf("%v not convertible to %T", v, zero)}, nil
+		}
+
+		for _, f := range fns {
+			response, err := f.Check(converted)
+			if err != nil || !response.IsValid {
+				return response, err
+			}
+		}
+
+		return Response{IsValid: true}, nil
+	})
+}
+
+// Between checks if a value is between lower and upper, inclusive
+func Between[T cmp.Ordered](lower T, upper T) TypedFunc[T] {
+	message := fmt.Sprintf("must be between %v and %v", lower, upper)
+
+	return WithTypedMeta(Meta{
+		Name:        "Between",
+		Description: message,
+		Params:      []Param{Param{Name: "lower", Value: lower}, Param{Name: "upper", Value: upper}},
+		ErrorCodes:  []string{"between"},
+	}, func(v T) (Response, error) {
+		if lower <= v && v <= upper {
+			return Response{IsValid: true}, nil
+		}
+
+		return Response{IsValid: false, Error: message}, nil
+	})
+}
+
+// Min checks if a value is at least min
+func Min[T cmp.Ordered](min T) TypedFunc[T] {
+	message := fmt.Sprintf("must be at least %v", min)
+
+	return WithTypedMeta(Meta{
+		Name:        "Min",
+		Description: message,
+		Params:      []Param{Param{Name: "min", Value: min}},
+		ErrorCodes:  []string{"min"},
+	}, func(v T) (Response, error) {
+		if min <= v {
+			return Response{IsValid: true}, nil
+		}
+
+		return Response{IsValid: false, Error: message}, nil
+	})
+}
+
+// Max checks if a value is at most max
+func Max[T cmp.Ordered](max T) TypedFunc[T] {
+	message := fmt.Sprintf("must be at most %v", max)
+
+	return WithTypedMeta(Meta{
+		Name:        "Max",
+		Description: message,
+		Params:      []Param{Param{Name: "max", Value: max}},
+		ErrorCodes:  []string{"max"},
+	}, func(v T) (Response, error) {
+		if v <= max {
+			return Response{IsValid: true}, nil
+		}
+
+		return Response{IsValid: false, Error: message}, nil
+	})
+}
+
+// OneOf checks if a value is one of a set of values
+func OneOf[T comparable](values ...T) TypedFunc[T] {
+	set := make(map[T]struct{}, len(values))
+	allowed := make([]string, len(values))
+	params := make([]interface{}, len(values))
+	for i, value := range values {
+		set[value] = struct{}{}
+		allowed[i] = fmt.Sprint(value)
+		params[i] = value
+	}
+	message := fmt.Sprintf("must be one of %s", strings.Join(allowed, ", "))
+
+	return WithTypedMeta(Meta{
+		Name:        "OneOf",
+		Description: message,
+		Params:      []Param{Param{Name: "values", Value: params}},
+		ErrorCodes:  []string{"one_of"},
+	}, func(v T) (Response, error) {
+		if _, ok := set[v]; ok {
+			return Response{IsValid: true}, nil
+		}
+
+		return Response{IsValid: false, Error: message}, nil
+	})
+}
@@ -0,0 +1,37 @@
+package funcs
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestTypedFuncs(t *testing.T) {
+	typedTests := []struct {
+		f       Func
+		value   interface{}
+		isValid bool
+	}{
+		{f: Untyped(Between(1, 100)), value: 53, isValid: true},
+		{f: Untyped(Between(1, 100)), value: 101, isValid: false},
+		{f: Untyped(Between(1, 100)), value: int64(53), isValid: false},
+		{f: Untyped(Between(1.5, 2.5)), value: 2.0, isValid: true},
+		{f: Untyped(Min("b")), value: "a", isValid: false},
+		{f: Untyped(Max(uint8(10))), value: uint8(10), isValid: true},
+		{f: Untyped(OneOf("draft", "submitted")), value: "draft", isValid: true},
+		{f: Untyped(OneOf("draft", "submitted")), value: "shipped", isValid: false},
+		{f: Untyped(Convert(strconv.Atoi, Between(1, 100))), value: "53", isValid: true},
+		{f: Untyped(Convert(strconv.Atoi, Between(1, 100))), value: "abc", isValid: false},
+		{f: Untyped(Typed[int](IsInt)), value: 1, isValid: true},
+	}
+
+	for i, test := range typedTests {
+		r, err := test.f(test.value)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if r.IsValid != test.isValid {
+			t.Errorf("Test %d: %v should have validity %t. Error: %s", i, test.value, test.isValid, r.Error)
+		}
+	}
+}
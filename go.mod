@@ -1 +1,3 @@
 module github.com/nmante/validator
+
+go 1.21
@@ -3,6 +3,7 @@ package validator
 import (
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/nmante/validator/funcs"
 )
@@ -16,11 +17,46 @@ type Job interface {
 	Run(wg *sync.WaitGroup)
 }
 
+// canceler is shared between jobs that should stop running as soon as one of them fails.
+// A nil canceler is never canceled
+type canceler struct {
+	once sync.Once
+	done chan struct{}
+}
+
+func newCanceler() *canceler {
+	return &canceler{done: make(chan struct{})}
+}
+
+func (c *canceler) cancel() {
+	if c == nil {
+		return
+	}
+
+	c.once.Do(func() { close(c.done) })
+}
+
+func (c *canceler) isCanceled() bool {
+	if c == nil {
+		return false
+	}
+
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
 type FuncJob struct {
 	value         interface{}
 	validatorFunc funcs.Func
+	canceler      *canceler
 	Err           error
 	Result        funcs.Response
+	Skipped       bool
+	Duration      time.Duration
 }
 
 func NewFuncJob(value interface{}, validatorFunc funcs.Func, options ...func(*FuncJob) error) (*FuncJob, error) {
@@ -43,18 +79,43 @@ func NewFuncJob(value interface{}, validatorFunc funcs.Func, options ...func(*Fu
 	return funcJob, nil
 }
 
+// withFuncCanceler makes the job skip its func once c is canceled, and cancel c when the func fails
+func withFuncCanceler(c *canceler) func(*FuncJob) error {
+	return func(j *FuncJob) error {
+		j.canceler = c
+		return nil
+	}
+}
+
 func (j *FuncJob) Run(wg *sync.WaitGroup) {
+	defer wg.Done()
+	j.run()
+}
+
+func (j *FuncJob) run() {
+	if j.canceler.isCanceled() {
+		j.Skipped = true
+		return
+	}
+
+	start := time.Now()
 	response, err := j.validatorFunc(j.value)
+	j.Duration = time.Since(start)
 	j.Err = err
 	j.Result = response
-	wg.Done()
+
+	if err != nil || !response.IsValid && response.Severity == funcs.SeverityError {
+		j.canceler.cancel()
+	}
 }
 
 type RuleJob struct {
-	value  interface{}
-	rule   Rule
-	Err    error
-	Result RuleResponse
+	value    interface{}
+	rule     Rule
+	canceler *canceler
+	Err      error
+	Result   RuleResponse
+	Skipped  bool
 }
 
 func NewRuleJob(value interface{}, rule Rule, options ...func(*RuleJob) error) (*RuleJob, error) {
@@ -73,9 +134,30 @@ func NewRuleJob(value interface{}, rule Rule, options ...func(*RuleJob) error) (
 	return ruleJob, nil
 }
 
+// withRuleCanceler makes the job skip its rule once c is canceled, and cancel c when the rule is invalid
+func withRuleCanceler(c *canceler) func(*RuleJob) error {
+	return func(j *RuleJob) error {
+		j.canceler = c
+		return nil
+	}
+}
+
 func (j *RuleJob) Run(wg *sync.WaitGroup) {
+	defer wg.Done()
+	j.run()
+}
+
+func (j *RuleJob) run() {
+	if j.canceler.isCanceled() {
+		j.Skipped = true
+		return
+	}
+
 	response, err := j.rule.execute(j.value)
 	j.Result = response
 	j.Err = err
-	wg.Done()
+
+	if err != nil || !response.IsValid {
+		j.canceler.cancel()
+	}
 }
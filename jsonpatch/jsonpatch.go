@@ -0,0 +1,577 @@
+// Package jsonpatch validates the documents that result from applying an RFC 6902 JSON Patch or an
+// RFC 7396 JSON Merge Patch, and maps validation errors back to the parts of the patch that
+// introduced them.
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/nmante/validator"
+)
+
+var (
+	ErrNotObject    = errors.New("Patched document must be an object")
+	ErrPathNotFound = errors.New("Path does not exist")
+	ErrTestFailed   = errors.New("Test operation failed")
+	ErrMissingValue = errors.New("Operation must have a value")
+)
+
+// Operation is a JSON Patch operation. Value is nil when the operation has no value, and "null" when
+// it's a JSON null
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Result is the validation of a patched document. PatchErrors holds the errors the patch introduced,
+// keyed by a JSON pointer into the patch: "/ops/2" for the third JSON Patch operation, or the path
+// of the member that set the value for a Merge Patch. Errors that were already in the original
+// document are only in Response
+type Result struct {
+	validator.Response
+	Document    map[string]interface{}
+	PatchErrors map[string][]validator.FieldError
+}
+
+// Validate applies a JSON Patch to a copy of original, and validates the result with v. Numbers in the
+// patch get the type of the number they replace, so a patched int field is still an int
+func Validate(v *validator.Validator, original map[string]interface{}, patch []byte) (Result, error) {
+	ops := []Operation{}
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return Result{}, err
+	}
+
+	doc, ops, err := apply(original, ops)
+	if err != nil {
+		return Result{}, err
+	}
+
+	response, err := v.Validate(doc)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Response: response, Document: doc, PatchErrors: map[string][]validator.FieldError{}}
+	for _, fe := range response.FieldErrors() {
+		pointer := keyToPointer(fe.Key)
+
+		// the last operation touching the value is the one that left it invalid
+		for i := len(ops) - 1; i >= 0; i-- {
+			if touches(ops[i], pointer) {
+				opPointer := "/ops/" + strconv.Itoa(i)
+				result.PatchErrors[opPointer] = append(result.PatchErrors[opPointer], fe)
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// ValidateMerge applies a JSON Merge Patch to a copy of original, and validates the result with v. Like
+// Validate, numbers in the patch get the type of the number they replace
+func ValidateMerge(v *validator.Validator, original map[string]interface{}, patch []byte) (Result, error) {
+	mergePatch, err := decode(patch)
+	if err != nil {
+		return Result{}, err
+	}
+
+	mergePatch = conform(mergePatch, original)
+
+	doc, ok := ApplyMerge(deepCopy(original), mergePatch).(map[string]interface{})
+	if !ok {
+		return Result{}, ErrNotObject
+	}
+
+	response, err := v.Validate(doc)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Response: response, Document: doc, PatchErrors: map[string][]validator.FieldError{}}
+	for _, fe := range response.FieldErrors() {
+		if pointer, ok := mergeSource(mergePatch, parsePointer(keyToPointer(fe.Key))); ok {
+			result.PatchErrors[pointer] = append(result.PatchErrors[pointer], fe)
+		}
+	}
+
+	return result, nil
+}
+
+// Apply applies JSON Patch operations to a copy of original
+func Apply(original map[string]interface{}, ops []Operation) (map[string]interface{}, error) {
+	doc, _, err := apply(original, ops)
+	return doc, err
+}
+
+// apply applies operations to a copy of original. It also returns the operations with "-" array
+// indexes resolved to the index the value was added at
+func apply(original map[string]interface{}, ops []Operation) (map[string]interface{}, []Operation, error) {
+	var doc interface{} = deepCopy(original)
+	resolved := make([]Operation, len(ops))
+
+	for i, op := range ops {
+		resolved[i] = op
+		if strings.HasSuffix(op.Path, "/-") {
+			parent, err := get(doc, parsePointer(strings.TrimSuffix(op.Path, "/-")))
+			if array, ok := parent.([]interface{}); ok && err == nil {
+				resolved[i].Path = strings.TrimSuffix(op.Path, "-") + strconv.Itoa(len(array))
+			}
+		}
+
+		var err error
+		doc, err = applyOperation(doc, op)
+		if err != nil {
+			return nil, nil, fmt.Errorf("/ops/%d: %w", i, err)
+		}
+	}
+
+	patched, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, nil, ErrNotObject
+	}
+
+	return patched, resolved, nil
+}
+
+func applyOperation(doc interface{}, op Operation) (interface{}, error) {
+	path := parsePointer(op.Path)
+
+	var value interface{}
+	if op.Op == "add" || op.Op == "replace" || op.Op == "test" {
+		if len(op.Value) == 0 {
+			return nil, fmt.Errorf("%w: %s at %s", ErrMissingValue, op.Op, op.Path)
+		}
+
+		decoded, err := decode(op.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		value = conform(decoded, replaced(doc, path))
+	}
+
+	switch op.Op {
+	case "add":
+		return set(doc, path, value, true)
+	case "replace":
+		return set(doc, path, value, false)
+	case "remove":
+		return remove(doc, path)
+	case "move", "copy":
+		from := parsePointer(op.From)
+		value, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+
+		if op.Op == "move" {
+			if doc, err = remove(doc, from); err != nil {
+				return nil, err
+			}
+		}
+
+		return set(doc, path, deepCopy(value), true)
+	case "test":
+		current, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !jsonEqual(current, value) {
+			return nil, fmt.Errorf("%w at %s", ErrTestFailed, op.Path)
+		}
+
+		return doc, nil
+	}
+
+	return nil, fmt.Errorf("Unknown operation %q", op.Op)
+}
+
+// ApplyMerge applies a JSON Merge Patch to target, and returns the result
+func ApplyMerge(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return deepCopy(patch)
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+
+		targetObject[key] = ApplyMerge(targetObject[key], value)
+	}
+
+	return targetObject
+}
+
+// mergeSource returns a pointer to the member of a merge patch that set the value at path
+func mergeSource(patch interface{}, path []string) (string, bool) {
+	pointer := ""
+	for _, token := range path {
+		object, ok := patch.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		value, ok := object[token]
+		if !ok {
+			return "", false
+		}
+
+		pointer += "/" + escapeToken(token)
+		patch = value
+	}
+
+	return pointer, pointer != ""
+}
+
+// touches checks if an operation changed the value at pointer, or one of its parents or children
+func touches(op Operation, pointer string) bool {
+	paths := []string{op.Path}
+	if op.Op == "move" {
+		paths = append(paths, op.From)
+	}
+
+	for _, path := range paths {
+		if op.Op != "test" && (isPrefix(path, pointer) || isPrefix(pointer, path)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isPrefix checks if pointer a is b or one of b's parents
+func isPrefix(a string, b string) bool {
+	return a == b || a == "" || strings.HasPrefix(b, a+"/")
+}
+
+func get(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch container := doc.(type) {
+		case map[string]interface{}:
+			value, ok := container[token]
+			if !ok {
+				return nil, pathError(path)
+			}
+			doc = value
+		case []interface{}:
+			i, err := index(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = container[i]
+		default:
+			return nil, pathError(path)
+		}
+	}
+
+	return doc, nil
+}
+
+// set adds or replaces the value at path. Adding to an array inserts the value
+func set(doc interface{}, path []string, value interface{}, add bool) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	token, rest := path[0], path[1:]
+
+	switch container := doc.(type) {
+	case map[string]interface{}:
+		child, ok := container[token]
+		if !ok && (len(rest) > 0 || !add) {
+			return nil, pathError(path)
+		}
+
+		updated, err := set(child, rest, value, add)
+		if err != nil {
+			return nil, err
+		}
+
+		container[token] = updated
+		return container, nil
+	case []interface{}:
+		if len(rest) == 0 && add {
+			i := len(container)
+			if token != "-" {
+				var err error
+				if i, err = index(token, len(container)); err != nil {
+					return nil, err
+				}
+			}
+
+			container = append(container, nil)
+			copy(container[i+1:], container[i:])
+			container[i] = value
+			return container, nil
+		}
+
+		i, err := index(token, len(container)-1)
+		if err != nil {
+			return nil, err
+		}
+
+		updated, err := set(container[i], rest, value, add)
+		if err != nil {
+			return nil, err
+		}
+
+		container[i] = updated
+		return container, nil
+	}
+
+	return nil, pathError(path)
+}
+
+func remove(doc interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, pathError(path)
+	}
+
+	token, rest := path[0], path[1:]
+
+	switch container := doc.(type) {
+	case map[string]interface{}:
+		child, ok := container[token]
+		if !ok {
+			return nil, pathError(path)
+		}
+
+		if len(rest) == 0 {
+			delete(container, token)
+			return container, nil
+		}
+
+		updated, err := remove(child, rest)
+		if err != nil {
+			return nil, err
+		}
+
+		container[token] = updated
+		return container, nil
+	case []interface{}:
+		i, err := index(token, len(container)-1)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rest) == 0 {
+			return append(container[:i], container[i+1:]...), nil
+		}
+
+		updated, err := remove(container[i], rest)
+		if err != nil {
+			return nil, err
+		}
+
+		container[i] = updated
+		return container, nil
+	}
+
+	return nil, pathError(path)
+}
+
+// index parses an array index, which must be between 0 and max
+func index(token string, max int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > max || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index %q", ErrPathNotFound, token)
+	}
+
+	return i, nil
+}
+
+func pathError(path []string) error {
+	return fmt.Errorf("%w: %s", ErrPathNotFound, toPointer(path))
+}
+
+// parsePointer splits a JSON pointer into unescaped tokens
+func parsePointer(pointer string) []string {
+	if pointer == "" {
+		return []string{}
+	}
+
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens
+}
+
+func toPointer(tokens []string) string {
+	pointer := ""
+	for _, token := range tokens {
+		pointer += "/" + escapeToken(token)
+	}
+
+	return pointer
+}
+
+func escapeToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// keyToPointer converts a validator key like "items[2].sku" to a JSON pointer like "/items/2/sku"
+func keyToPointer(key string) string {
+	tokens := []string{}
+	for _, part := range strings.Split(key, ".") {
+		name, indexes, _ := strings.Cut(part, "[")
+		if name != "" {
+			tokens = append(tokens, name)
+		}
+
+		if indexes != "" {
+			for _, i := range strings.Split(strings.TrimSuffix(indexes, "]"), "][") {
+				tokens = append(tokens, i)
+			}
+		}
+	}
+
+	return toPointer(tokens)
+}
+
+// decode decodes a JSON value, keeping its numbers as json.Numbers for conform
+func decode(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// replaced returns the value at path that a patch value replaces. An array element that's added
+// replaces nothing, so it's compared with the array's first element instead
+func replaced(doc interface{}, path []string) interface{} {
+	if value, err := get(doc, path); err == nil {
+		return value
+	}
+
+	if len(path) > 0 {
+		if array, ok := replaced(doc, path[:len(path)-1]).([]interface{}); ok && len(array) > 0 {
+			return array[0]
+		}
+	}
+
+	return nil
+}
+
+// conform converts the json.Numbers in value to the type of the number at the same place in like.
+// Numbers with no number to match become an int when they're whole, and a float64 otherwise
+func conform(value interface{}, like interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		likeObject, _ := like.(map[string]interface{})
+		for key, child := range v {
+			v[key] = conform(child, likeObject[key])
+		}
+	case []interface{}:
+		likeArray, _ := like.([]interface{})
+		for i, child := range v {
+			var likeChild interface{}
+			if i < len(likeArray) {
+				likeChild = likeArray[i]
+			} else if len(likeArray) > 0 {
+				likeChild = likeArray[0]
+			}
+
+			v[i] = conform(child, likeChild)
+		}
+	case json.Number:
+		return toNumber(v, like)
+	}
+
+	return value
+}
+
+// toNumber converts n to the type of like if like is a number that can hold it
+func toNumber(n json.Number, like interface{}) interface{} {
+	likeValue := reflect.ValueOf(like)
+	number := reflect.New(reflect.TypeOf(0)).Elem()
+	if likeValue.IsValid() {
+		number = reflect.New(likeValue.Type()).Elem()
+	}
+
+	switch number.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, err := strconv.ParseInt(n.String(), 10, 64); err == nil && !number.OverflowInt(i) {
+			number.SetInt(i)
+			return number.Interface()
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if u, err := strconv.ParseUint(n.String(), 10, 64); err == nil && !number.OverflowUint(u) {
+			number.SetUint(u)
+			return number.Interface()
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, err := n.Float64(); err == nil && !number.OverflowFloat(f) {
+			number.SetFloat(f)
+			return number.Interface()
+		}
+	}
+
+	if i, err := strconv.Atoi(n.String()); err == nil {
+		return i
+	}
+
+	f, _ := n.Float64()
+	return f
+}
+
+// deepCopy copies the maps and slices of a JSON document
+func deepCopy(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			copied[key] = deepCopy(child)
+		}
+
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, child := range v {
+			copied[i] = deepCopy(child)
+		}
+
+		return copied
+	}
+
+	return value
+}
+
+// jsonEqual checks if two values have the same JSON encoding
+func jsonEqual(a interface{}, b interface{}) bool {
+	left, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+
+	right, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+
+	return string(left) == string(right)
+}
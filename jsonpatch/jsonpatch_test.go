@@ -0,0 +1,183 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/nmante/validator"
+	"github.com/nmante/validator/compare"
+	"github.com/nmante/validator/funcs"
+	"github.com/nmante/validator/transform"
+)
+
+func newOrderValidator() *validator.Validator {
+	item, _ := validator.New([]validator.Rule{
+		validator.Rule{Key: "sku", IsRequired: true, Funcs: []funcs.Func{funcs.IsLengthBetween(1, 8)}},
+	})
+
+	v, _ := validator.New([]validator.Rule{
+		validator.Rule{Key: "status", IsRequired: true, Funcs: []funcs.Func{funcs.Untyped(funcs.OneOf("draft", "submitted"))}},
+		validator.Rule{Key: "note", Funcs: []funcs.Func{funcs.IsLengthBetween(1, 5)}},
+		validator.Rule{Key: "items", Funcs: []funcs.Func{validator.EachNested(item)}},
+	})
+
+	return v
+}
+
+func newOrder() map[string]interface{} {
+	return map[string]interface{}{
+		"status": "draft",
+		"note":   "a note that is too long",
+		"items":  []interface{}{map[string]interface{}{"sku": "a"}},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	original := newOrder()
+
+	result, err := Validate(newOrderValidator(), original, []byte(`[
+		{"op": "test", "path": "/status", "value": "draft"},
+		{"op": "replace", "path": "/status", "value": "shipped"},
+		{"op": "copy", "from": "/items/0", "path": "/items/0"},
+		{"op": "add", "path": "/items/-", "value": {"sku": "much too long"}}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]validator.FieldError{
+		"/ops/1": []validator.FieldError{{Key: "status", Message: "must be one of draft, submitted"}},
+		"/ops/3": []validator.FieldError{{Key: "items[2].sku", Message: "Must be between length 1 and 8"}},
+	}
+
+	if !reflect.DeepEqual(result.PatchErrors, expected) {
+		t.Errorf("Patch errors should be %+v. They are %+v", expected, result.PatchErrors)
+	}
+
+	if len(result.Errors["note"]) != 1 {
+		t.Errorf("Errors in the original document should still be reported. Errors: %+v", result.Errors)
+	}
+
+	if original["status"] != "draft" || len(original["items"].([]interface{})) != 1 {
+		t.Errorf("The original document should not change. It is %+v", original)
+	}
+
+	patchErrorTests := []struct {
+		patch string
+		err   error
+	}{
+		{patch: `[{"op": "test", "path": "/status", "value": "submitted"}]`, err: ErrTestFailed},
+		{patch: `[{"op": "replace", "path": "/missing", "value": 1}]`, err: ErrPathNotFound},
+		{patch: `[{"op": "remove", "path": "/items/5"}]`, err: ErrPathNotFound},
+		{patch: `[{"op": "replace", "path": "", "value": []}]`, err: ErrNotObject},
+		{patch: `[{"op": "add", "path": "/note"}]`, err: ErrMissingValue},
+		{patch: `[{"op": "replace", "path": "/status"}]`, err: ErrMissingValue},
+		{patch: `[{"op": "test", "path": "/status"}]`, err: ErrMissingValue},
+	}
+
+	for _, test := range patchErrorTests {
+		if _, err := Validate(newOrderValidator(), newOrder(), []byte(test.patch)); !errors.Is(err, test.err) {
+			t.Errorf("%s should fail with %v. Error: %v", test.patch, test.err, err)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	doc, err := Apply(map[string]interface{}{"a": []interface{}{1.0, 2.0}, "b": map[string]interface{}{"c": "d"}}, []Operation{
+		{Op: "add", Path: "/a/1", Value: json.RawMessage(`1.5`)},
+		{Op: "add", Path: "/a/-", Value: json.RawMessage(`3`)},
+		{Op: "move", From: "/b/c", Path: "/e~1f"},
+		{Op: "remove", Path: "/a/0"},
+		{Op: "add", Path: "/g", Value: json.RawMessage(`null`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{"a": []interface{}{1.5, 2.0, 3.0}, "b": map[string]interface{}{}, "e/f": "d", "g": nil}
+	if !reflect.DeepEqual(doc, expected) {
+		t.Errorf("Document should be %+v. It is %+v", expected, doc)
+	}
+}
+
+func TestValidateMerge(t *testing.T) {
+	result, err := ValidateMerge(newOrderValidator(), newOrder(), []byte(`{
+		"status": null,
+		"note": "short",
+		"items": [{"sku": ""}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]validator.FieldError{
+		"/status": []validator.FieldError{{Key: "status", Message: "is required"}},
+		"/items":  []validator.FieldError{{Key: "items[0].sku", Message: "Must be between length 1 and 8"}},
+	}
+
+	if !reflect.DeepEqual(result.PatchErrors, expected) {
+		t.Errorf("Patch errors should be %+v. They are %+v", expected, result.PatchErrors)
+	}
+
+	if _, ok := result.Errors["note"]; ok {
+		t.Errorf("The patched note should be valid. Errors: %+v", result.Errors)
+	}
+}
+
+func TestPatchNumbers(t *testing.T) {
+	v, _ := validator.New([]validator.Rule{
+		validator.Rule{Key: "count", Funcs: []funcs.Func{funcs.IsBetween(transform.None, compare.Int, 1, 100)}},
+		validator.Rule{Key: "price", Funcs: []funcs.Func{funcs.IsBetween(transform.None, compare.Float64, 1.0, 100.0)}},
+		validator.Rule{Key: "sizes", Funcs: []funcs.Func{funcs.Each(funcs.IsBetween(transform.None, compare.Int, 1, 10))}},
+	})
+
+	original := map[string]interface{}{"count": 5, "price": 9.5, "sizes": []interface{}{1, 2}}
+
+	tests := []struct {
+		patch    string
+		document map[string]interface{}
+		errors   []string
+	}{
+		{
+			patch:    `[{"op": "replace", "path": "/count", "value": 50}, {"op": "replace", "path": "/price", "value": 10}]`,
+			document: map[string]interface{}{"count": 50, "price": 10.0, "sizes": []interface{}{1, 2}},
+			errors:   []string{},
+		},
+		{
+			patch:    `[{"op": "replace", "path": "/count", "value": 500}, {"op": "add", "path": "/sizes/-", "value": 20}]`,
+			document: map[string]interface{}{"count": 500, "price": 9.5, "sizes": []interface{}{1, 2, 20}},
+			errors:   []string{"count", "sizes[2]"},
+		},
+	}
+
+	for _, test := range tests {
+		result, err := Validate(v, original, []byte(test.patch))
+		if err != nil {
+			t.Fatalf("%s should apply. Error: %v", test.patch, err)
+		}
+
+		if !reflect.DeepEqual(result.Document, test.document) {
+			t.Errorf("%s should give %#v. It gives %#v", test.patch, test.document, result.Document)
+		}
+
+		keys := []string{}
+		for _, fe := range result.FieldErrors() {
+			keys = append(keys, fe.Key)
+		}
+
+		if !reflect.DeepEqual(keys, test.errors) {
+			t.Errorf("%s should give errors for %v. Errors: %+v", test.patch, test.errors, result.FieldErrors())
+		}
+	}
+
+	result, err := ValidateMerge(v, original, []byte(`{"count": 500, "price": 20}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Document["count"] != 500 || result.Document["price"] != 20.0 || len(result.PatchErrors["/count"]) != 1 {
+		t.Errorf("Merge patch numbers should have the type of the numbers they replace. Result: %+v", result)
+	}
+}
@@ -0,0 +1,131 @@
+package validator
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// KeyNormalization is a set of flags for matching input keys to rule keys loosely
+type KeyNormalization int
+
+const (
+	// KeyFoldCase matches keys case insensitively, so "Page_Size" matches "page_size"
+	KeyFoldCase KeyNormalization = 1 << iota
+	// KeySnakeCamel matches snake_case, camelCase and kebab-case keys, so "pageSize" and "page-size"
+	// match "page_size"
+	KeySnakeCamel
+)
+
+// normalizeKey returns the form of a key used to match it against rule keys and aliases
+func (n KeyNormalization) normalizeKey(key string) string {
+	if n&KeySnakeCamel != 0 {
+		key = camelToSnake(key)
+	}
+
+	if n&KeyFoldCase != 0 {
+		key = strings.ToLower(key)
+	}
+
+	return key
+}
+
+func camelToSnake(key string) string {
+	var b strings.Builder
+	var previous rune
+
+	for i, r := range key {
+		switch {
+		case r == '-':
+			r = '_'
+		case i > 0 && unicode.IsUpper(r) && (unicode.IsLower(previous) || unicode.IsDigit(previous)):
+			b.WriteRune('_')
+			r = unicode.ToLower(r)
+		}
+
+		b.WriteRune(r)
+		previous = r
+	}
+
+	return b.String()
+}
+
+// canonicalize renames input keys that match a rule key or alias to the rule key. If more than one
+// input key matches the same rule, the one that equals the rule key is kept, or else the first in
+// sorted order, and the input keys are returned as conflicts by rule key
+func (v *Validator) canonicalize(values map[string]interface{}) (map[string]interface{}, map[string][]string) {
+	if v.normalization == 0 && !v.hasAliases() {
+		return values, nil
+	}
+
+	lookup := map[string]string{}
+	for _, rule := range v.rules {
+		for _, alias := range rule.Aliases {
+			lookup[v.normalization.normalizeKey(alias)] = rule.Key
+		}
+	}
+
+	// rule keys take precedence over aliases that normalize the same way
+	for _, rule := range v.rules {
+		lookup[v.normalization.normalizeKey(rule.Key)] = rule.Key
+	}
+
+	canonical := map[string]interface{}{}
+	sources := map[string][]string{}
+
+	for _, key := range sortedKeys(values) {
+		target, ok := lookup[v.normalization.normalizeKey(key)]
+		if !ok {
+			target = key
+		}
+
+		sources[target] = append(sources[target], key)
+		if _, exists := canonical[target]; !exists || key == target {
+			canonical[target] = values[key]
+		}
+	}
+
+	conflicts := map[string][]string{}
+	for target, keys := range sources {
+		if len(keys) > 1 {
+			conflicts[target] = keys
+		}
+	}
+
+	return canonical, conflicts
+}
+
+func (v *Validator) hasAliases() bool {
+	for _, rule := range v.rules {
+		if len(rule.Aliases) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addConflicts reports input keys that matched the same rule, in rule order
+func (v *Validator) addConflicts(conflicts map[string][]string, response *Response) {
+	keys := []string{}
+	for key := range conflicts {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return v.ruleOrder(keys[i]) < v.ruleOrder(keys[j])
+	})
+
+	for _, key := range keys {
+		response.AddError(key, "conflicting keys: "+strings.Join(conflicts[key], ", "))
+	}
+}
+
+// ruleOrder returns the position of a key's rule, or the number of rules for keys without one
+func (v *Validator) ruleOrder(key string) int {
+	if i, ok := v.index[key]; ok {
+		return i
+	}
+
+	return len(v.rules)
+}
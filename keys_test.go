@@ -0,0 +1,76 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nmante/validator/funcs"
+)
+
+func TestNormalizeKey(t *testing.T) {
+	normalizeTests := []struct {
+		normalization KeyNormalization
+		key           string
+		normalized    string
+	}{
+		{normalization: KeySnakeCamel, key: "pageSize", normalized: "page_size"},
+		{normalization: KeySnakeCamel, key: "page-size", normalized: "page_size"},
+		{normalization: KeySnakeCamel, key: "PageSize", normalized: "Page_size"},
+		{normalization: KeySnakeCamel, key: "item2Id", normalized: "item2_id"},
+		{normalization: KeyFoldCase, key: "Page_Size", normalized: "page_size"},
+		{normalization: KeyFoldCase | KeySnakeCamel, key: "PageSize", normalized: "page_size"},
+		{normalization: 0, key: "PageSize", normalized: "PageSize"},
+	}
+
+	for _, test := range normalizeTests {
+		if normalized := test.normalization.normalizeKey(test.key); normalized != test.normalized {
+			t.Errorf("%s should normalize to %s. It normalizes to %s", test.key, test.normalized, normalized)
+		}
+	}
+}
+
+func TestAliasesAndNormalization(t *testing.T) {
+	validator, _ := New(
+		[]Rule{
+			Rule{Key: "page_size", IsRequired: true, Funcs: []funcs.Func{funcs.IsInt}},
+			Rule{Key: "query", Aliases: []string{"q", "search"}, Funcs: []funcs.Func{funcs.IsLengthBetween(1, 10)}},
+		},
+		OptionKeyNormalization(KeyFoldCase|KeySnakeCamel),
+	)
+
+	r, err := validator.Validate(map[string]interface{}{
+		"PageSize": "10",
+		"Q":        "a very long query",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []FieldError{
+		FieldError{Key: "page_size", Message: "must be a int"},
+		FieldError{Key: "query", Message: "Must be between length 1 and 10"},
+	}
+
+	if !reflect.DeepEqual(r.FieldErrors(), expected) {
+		t.Errorf("Errors should be %+v. They are %+v", expected, r.FieldErrors())
+	}
+
+	r, err = validator.Validate(map[string]interface{}{
+		"page_size": 10,
+		"pageSize":  "10",
+		"search":    "go",
+		"q":         "rust",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = []FieldError{
+		FieldError{Key: "page_size", Message: "conflicting keys: pageSize, page_size"},
+		FieldError{Key: "query", Message: "conflicting keys: q, search"},
+	}
+
+	if !reflect.DeepEqual(r.FieldErrors(), expected) {
+		t.Errorf("Errors should be %+v. They are %+v", expected, r.FieldErrors())
+	}
+}
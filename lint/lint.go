@@ -0,0 +1,543 @@
+// Package lint finds rules that can never pass, or that don't do what they look like they do,
+// like a key that must be between 1 and 100 and also equal to 500. It checks the funcs.Meta of
+// each rule's Funcs, so Funcs without metadata aren't checked. Constraints are checked by what they
+// allow when they run, like IsBetween allowing both of its bounds. Problems with Funcs that only
+// report warnings or infos, like those wrapped with funcs.Warning, are warnings.
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/nmante/validator"
+	"github.com/nmante/validator/funcs"
+)
+
+const (
+	CheckDisjointRanges   = "disjoint-ranges"
+	CheckEmptyRange       = "empty-range"
+	CheckConflictingTypes = "conflicting-types"
+	CheckDisjointLengths  = "disjoint-lengths"
+	CheckEmptyLength      = "empty-length"
+	CheckRequiredDefault  = "required-default"
+	CheckDuplicateFunc    = "duplicate-func"
+	CheckEmptyRule        = "empty-rule"
+	CheckInvalidDefault   = "invalid-default"
+)
+
+// Finding is a problem with a rule. Key is the rule's key, with nested keys like "address.zip", or
+// a pattern rule's pattern. Check identifies the kind of problem
+type Finding struct {
+	Key      string
+	Check    string
+	Severity funcs.Severity
+	Message  string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s)", f.Key, f.Severity, f.Message, f.Check)
+}
+
+// Validator checks a validator's rules, including the rules of nested validators
+func Validator(v *validator.Validator) []Finding {
+	return Description(v.Describe())
+}
+
+// Description checks the rules of a validator's description, in rule order
+func Description(d validator.Description) []Finding {
+	l := &linter{findings: []Finding{}}
+	l.description("", d)
+
+	return l.findings
+}
+
+type linter struct {
+	findings []Finding
+}
+
+func (l *linter) add(key string, check string, severity funcs.Severity, format string, args ...interface{}) {
+	l.findings = append(l.findings, Finding{Key: key, Check: check, Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) description(prefix string, d validator.Description) {
+	for _, rule := range d.Rules {
+		key := prefix + rule.Key
+
+		if rule.Required && rule.Default != nil {
+			l.add(key, CheckRequiredDefault, funcs.SeverityWarning, "default %v is never used, because the key is required", rule.Default)
+		}
+
+		for scenario, required := range rule.RequiredFor {
+			if required && !rule.Required && rule.Default != nil {
+				l.add(key, CheckRequiredDefault, funcs.SeverityWarning, "default %v is never used in %s, because the key is required", rule.Default, scenario)
+			}
+		}
+
+		if len(rule.Funcs) == 0 && !rule.Required && rule.Deprecation == nil && len(rule.DeprecatedValues) == 0 {
+			l.add(key, CheckEmptyRule, funcs.SeverityInfo, "has no funcs and isn't required, so it never fails")
+		}
+
+		all := l.funcs(key, rule.Severity, rule.Funcs)
+		l.nested(key, rule.Funcs)
+
+		if rule.Default != nil {
+			l.defaults(key, rule.Default, all)
+		}
+	}
+
+	for _, p := range d.Patterns {
+		l.funcs(p.Pattern, p.Severity, p.Funcs)
+	}
+}
+
+// nested checks the validators nested in a key's Funcs
+func (l *linter) nested(key string, metas []funcs.Meta) {
+	for _, meta := range metas {
+		switch schema := meta.Schema.(type) {
+		case validator.Description:
+			l.description(key+".", schema)
+		case map[string]validator.Description:
+			for _, value := range sortedKeys(schema) {
+				l.description(key+".", schema[value])
+			}
+		}
+
+		prefix := key
+		if meta.Name == "EachNested" {
+			prefix += "[]"
+		}
+
+		l.nested(prefix, meta.Children)
+	}
+}
+
+// funcs checks the Funcs that all check a key's value, and returns them flattened. severity is the
+// rule's severity
+func (l *linter) funcs(key string, severity string, metas []funcs.Meta) []funcs.Meta {
+	all := flatten(metas, severity)
+
+	l.duplicates(key, metas)
+	l.ranges(key, all)
+	l.lengths(key, all)
+	l.types(key, all)
+
+	return all
+}
+
+// flatten returns the Funcs that must all pass, expanding And and Optional, and the TypedFuncs of
+// Untyped. Each Func gets a severity param with the severity it reports failures with, which it
+// takes from the Funcs it's in, or from the rule
+func flatten(metas []funcs.Meta, severity string) []funcs.Meta {
+	flat := []funcs.Meta{}
+	for _, meta := range metas {
+		meta = withSeverity(meta, severity)
+
+		switch meta.Name {
+		case "And", "Optional":
+			flat = append(flat, flatten(meta.Children, severityOf(meta).String())...)
+			continue
+		case "Untyped":
+			flat = append(flat, flatten(meta.Children, severityOf(meta).String())...)
+			meta.Children = nil
+		}
+
+		flat = append(flat, meta)
+	}
+
+	return flat
+}
+
+// withSeverity adds a severity param to a Func that doesn't have one. A rule's warning or info
+// severity overrides a Func's error severity, like when the rule runs
+func withSeverity(meta funcs.Meta, severity string) funcs.Meta {
+	if severity == "" || severity == funcs.SeverityError.String() {
+		return meta
+	}
+
+	if s, ok := meta.Param("severity"); ok && s != funcs.SeverityError.String() {
+		return meta
+	}
+
+	meta.Params = append(withoutSeverity(meta).Params, funcs.Param{Name: "severity", Value: severity})
+	return meta
+}
+
+// severityOf returns the severity a Func reports failures with
+func severityOf(meta funcs.Meta) funcs.Severity {
+	severity, _ := meta.Param("severity")
+	switch fmt.Sprint(severity) {
+	case funcs.SeverityWarning.String():
+		return funcs.SeverityWarning
+	case funcs.SeverityInfo.String():
+		return funcs.SeverityInfo
+	}
+
+	return funcs.SeverityError
+}
+
+// findingSeverity returns the severity of a problem with Funcs. It's an error only if every Func
+// reports errors, since otherwise the value is still valid
+func findingSeverity(metas ...funcs.Meta) funcs.Severity {
+	for _, meta := range metas {
+		if severityOf(meta) != funcs.SeverityError {
+			return funcs.SeverityWarning
+		}
+	}
+
+	return funcs.SeverityError
+}
+
+// withoutSeverity removes the severity param of a Func, so Funcs that only differ by severity
+// compare and print the same
+func withoutSeverity(meta funcs.Meta) funcs.Meta {
+	params := []funcs.Param{}
+	for _, p := range meta.Params {
+		if p.Name != "severity" {
+			params = append(params, p)
+		}
+	}
+
+	meta.Params = params
+	if len(params) == 0 {
+		meta.Params = nil
+	}
+
+	return meta
+}
+
+func (l *linter) duplicates(key string, metas []funcs.Meta) {
+	for i, meta := range metas {
+		if meta.Name == "" {
+			continue
+		}
+
+		for _, other := range metas[:i] {
+			if reflect.DeepEqual(withoutSeverity(meta), withoutSeverity(other)) {
+				l.add(key, CheckDuplicateFunc, funcs.SeverityWarning, "%s is checked more than once", label(meta))
+				break
+			}
+		}
+	}
+}
+
+// defaults checks that a rule's default passes its Funcs, as far as their Metas tell
+func (l *linter) defaults(key string, value interface{}, metas []funcs.Meta) {
+	for _, meta := range metas {
+		if !allowsDefault(meta, value) {
+			l.add(key, CheckInvalidDefault, funcs.SeverityWarning, "default %v doesn't pass %s", value, label(meta))
+		}
+	}
+}
+
+// allowsDefault checks if a Func allows a value. Funcs that can't be checked without running them
+// allow every value
+func allowsDefault(meta funcs.Meta, value interface{}) bool {
+	if b, ok := rangeOf(meta); ok {
+		n, ok := toFloat(value)
+		return !ok || b.lower <= n && n <= b.upper
+	}
+
+	if b, ok := lengthOf(meta); ok {
+		v := reflect.ValueOf(value)
+		switch v.Kind() {
+		case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+			return b.lower <= float64(v.Len()) && float64(v.Len()) <= b.upper
+		}
+
+		return false
+	}
+
+	switch meta.Name {
+	case "OneOf":
+		values, _ := meta.Param("values")
+		list, ok := values.([]interface{})
+		if !ok {
+			return true
+		}
+
+		for _, allowed := range list {
+			if equal(allowed, value) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if kind := kindOf(meta); kind != "" && !isGeneralKind(kind) {
+		return jsonKind(kind) == "" || jsonKind(kind) == valueKind(value)
+	}
+
+	return true
+}
+
+// equal compares two values, comparing numbers of different types by value
+func equal(a interface{}, b interface{}) bool {
+	x, xOK := toFloat(a)
+	y, yOK := toFloat(b)
+	if xOK && yOK {
+		return x == y
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// jsonKind returns the kind of JSON value a Go type is sent as, or "" if it isn't known
+func jsonKind(goType string) string {
+	switch {
+	case strings.HasPrefix(goType, "int"), strings.HasPrefix(goType, "uint"), strings.HasPrefix(goType, "float"):
+		return "number"
+	case goType == "string", goType == "bool":
+		return goType
+	case strings.HasPrefix(goType, "[]"):
+		return "array"
+	case strings.HasPrefix(goType, "map["):
+		return "object"
+	}
+
+	return ""
+}
+
+// valueKind returns the kind of JSON value a value is sent as
+func valueKind(value interface{}) string {
+	if _, ok := toFloat(value); ok {
+		return "number"
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map:
+		return "object"
+	}
+
+	return ""
+}
+
+// interval is the inclusive range of values a Func allows
+type interval struct {
+	lower float64
+	upper float64
+	meta  funcs.Meta
+}
+
+func (l *linter) ranges(key string, metas []funcs.Meta) {
+	intervals := []interval{}
+	for _, meta := range metas {
+		b, ok := rangeOf(meta)
+		if !ok {
+			continue
+		}
+
+		if b.lower > b.upper {
+			l.add(key, CheckEmptyRange, findingSeverity(meta), "%s can never pass, because its lower bound is greater than its upper bound", label(meta))
+			continue
+		}
+
+		intervals = append(intervals, b)
+	}
+
+	l.disjoint(key, CheckDisjointRanges, intervals)
+}
+
+func (l *linter) lengths(key string, metas []funcs.Meta) {
+	intervals := []interval{}
+	for _, meta := range metas {
+		b, ok := lengthOf(meta)
+		if !ok {
+			continue
+		}
+
+		if b.lower > b.upper || b.upper < 0 {
+			l.add(key, CheckEmptyLength, findingSeverity(meta), "%s can never pass, because no length is within its bounds", label(meta))
+			continue
+		}
+
+		intervals = append(intervals, b)
+	}
+
+	l.disjoint(key, CheckDisjointLengths, intervals)
+}
+
+// rangeOf returns the range of values a Func allows, if it limits them
+func rangeOf(meta funcs.Meta) (interval, bool) {
+	lower, upper, ok := funcs.Bounds(meta)
+	if !ok {
+		return interval{}, false
+	}
+
+	return newInterval(meta, lower, upper)
+}
+
+// lengthOf returns the range of lengths a Func allows, if it limits them
+func lengthOf(meta funcs.Meta) (interval, bool) {
+	lower, upper, ok := funcs.LengthBounds(meta)
+	if !ok {
+		return interval{}, false
+	}
+
+	return newInterval(meta, lower, upper)
+}
+
+// newInterval converts bounds to an interval. A nil bound doesn't limit the interval
+func newInterval(meta funcs.Meta, lower interface{}, upper interface{}) (interval, bool) {
+	low, lowOK := math.Inf(-1), true
+	if lower != nil {
+		low, lowOK = toFloat(lower)
+	}
+
+	high, highOK := math.Inf(1), true
+	if upper != nil {
+		high, highOK = toFloat(upper)
+	}
+
+	return interval{lower: low, upper: high, meta: meta}, lowOK && highOK
+}
+
+// disjoint reports pairs of intervals that don't overlap, so no value passes both
+func (l *linter) disjoint(key string, check string, intervals []interval) {
+	for i, a := range intervals {
+		for _, b := range intervals[:i] {
+			if a.lower > b.upper || b.lower > a.upper {
+				l.add(key, check, findingSeverity(a.meta, b.meta), "%s and %s can never both pass", label(b.meta), label(a.meta))
+			}
+		}
+	}
+}
+
+// requirement is the type of value a Func accepts. kind is a Go type name like "int", or a kind of
+// value like "collection"
+type requirement struct {
+	kind string
+	meta funcs.Meta
+}
+
+// kinds of value that accept more than one type
+const (
+	kindLength     = "something with a length"
+	kindCollection = "collection"
+	kindObject     = "object"
+)
+
+func (l *linter) types(key string, metas []funcs.Meta) {
+	requirements := []requirement{}
+	for _, meta := range metas {
+		if kind := kindOf(meta); kind != "" {
+			requirements = append(requirements, requirement{kind: kind, meta: meta})
+		}
+	}
+
+	for i, a := range requirements {
+		for _, b := range requirements[:i] {
+			if !compatible(a.kind, b.kind) {
+				l.add(key, CheckConflictingTypes, findingSeverity(a.meta, b.meta), "%s and %s can never both pass, because a value can't be both %s and %s", label(b.meta), label(a.meta), b.kind, a.kind)
+			}
+		}
+	}
+}
+
+// kindOf returns the type of value a Func accepts, or "" if it accepts any value or isn't known
+func kindOf(meta funcs.Meta) string {
+	switch meta.Name {
+	case "IsLength", "IsLengthBetween":
+		return kindLength
+	case "Each", "EachValue", "Unique", "UniqueBy", "Contains", "SubsetOf", "Sorted", "EachNested", "EachRef":
+		return kindCollection
+	case "Nested", "NestedDiscriminator", "Ref", "EachKey":
+		return kindObject
+	case "IsTransformableTo", "String.IsEmail":
+		return "string"
+	}
+
+	if strings.HasPrefix(meta.Name, "String.") {
+		return "string"
+	}
+
+	if _type, ok := funcs.CheckedType(meta); ok {
+		return _type
+	}
+
+	return ""
+}
+
+// compatible checks if some value can have both kinds
+func compatible(a string, b string) bool {
+	if a == b {
+		return true
+	}
+
+	// maps are collections and objects, and have a length
+	if isGeneralKind(a) && isGeneralKind(b) {
+		return true
+	}
+
+	if isGeneralKind(a) {
+		a, b = b, a
+	}
+
+	// a is a Go type name now
+	switch b {
+	case kindLength:
+		return a == "string" || strings.HasPrefix(a, "[]") || strings.HasPrefix(a, "map[")
+	case kindCollection:
+		return strings.HasPrefix(a, "[]") || strings.HasPrefix(a, "map[")
+	case kindObject:
+		return strings.HasPrefix(a, "map[")
+	}
+
+	return false
+}
+
+func isGeneralKind(kind string) bool {
+	return kind == kindLength || kind == kindCollection || kind == kindObject
+}
+
+// label formats a Func like "IsBetween(1, 100)", without its severity
+func label(meta funcs.Meta) string {
+	meta = withoutSeverity(meta)
+	values := make([]string, len(meta.Params))
+	for i, p := range meta.Params {
+		values[i] = fmt.Sprint(p.Value)
+	}
+
+	return meta.Name + "(" + strings.Join(values, ", ") + ")"
+}
+
+// toFloat converts a number of any type to a float64, including a json.Number from a decoded
+// description
+func toFloat(v interface{}) (float64, bool) {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	}
+
+	return 0, false
+}
+
+func sortedKeys(m map[string]validator.Description) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,129 @@
+package lint
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nmante/validator"
+	"github.com/nmante/validator/compare"
+	"github.com/nmante/validator/funcs"
+	"github.com/nmante/validator/transform"
+)
+
+func TestValidator(t *testing.T) {
+	address, _ := validator.New([]validator.Rule{
+		validator.Rule{Key: "zip", Funcs: []funcs.Func{funcs.IsLength(5), funcs.IsLengthBetween(6, 10)}},
+	})
+
+	v, _ := validator.New([]validator.Rule{
+		validator.Rule{Key: "qty", Funcs: []funcs.Func{funcs.IsBetween(transform.None, compare.Int, 1, 100), funcs.IsEqual(transform.None, compare.Int, 500)}},
+		validator.Rule{Key: "code", Funcs: []funcs.Func{funcs.IsBetween(transform.None, compare.Int, 1, 100), funcs.String.IsEqualToInt(500)}},
+		validator.Rule{Key: "pin", Funcs: []funcs.Func{funcs.String.IsInRangeInts(1000, 9999), funcs.IsLength(4)}},
+		validator.Rule{Key: "ok", Funcs: []funcs.Func{funcs.IsInt, funcs.IsBetween(transform.None, compare.Int, 1, 100), funcs.IsEqual(transform.None, compare.Int, 50)}},
+		validator.Rule{Key: "age", Funcs: []funcs.Func{funcs.IsBetween(transform.None, compare.Int, 10, 1)}},
+		validator.Rule{Key: "email", Funcs: []funcs.Func{funcs.IsInt, funcs.And(funcs.String.IsEmail)}},
+		validator.Rule{Key: "name", Funcs: []funcs.Func{funcs.IsLengthBetween(5, 2), funcs.IsType(reflect.TypeOf(""))}},
+		validator.Rule{Key: "count", Funcs: []funcs.Func{funcs.IsInt, funcs.IsLength(2)}},
+		validator.Rule{Key: "country", IsRequired: true, Default: "US"},
+		validator.Rule{Key: "tags", Funcs: []funcs.Func{funcs.Unique, funcs.IsLengthBetween(1, 3), funcs.Unique}},
+		validator.Rule{Key: "notes"},
+		validator.Rule{Key: "plan", Deprecation: &validator.Deprecation{Replacement: "tier"}},
+		validator.Rule{Key: "address", Funcs: []funcs.Func{validator.Nested(address)}},
+		validator.Rule{Key: "page", Funcs: []funcs.Func{funcs.Untyped(funcs.Between(1, 100)), funcs.Untyped(funcs.Min(200))}},
+		validator.Rule{Key: "limit", Funcs: []funcs.Func{funcs.Untyped(funcs.Max(10)), funcs.String.IsInt}},
+		validator.Rule{Key: "labels", Funcs: []funcs.Func{funcs.IsType(reflect.TypeOf([]string{})), funcs.IsLengthBetween(1, 5), funcs.Unique}},
+		validator.Rule{Key: "sizes", Funcs: []funcs.Func{funcs.IsType(reflect.TypeOf(map[string]int{})), funcs.IsInt}},
+	})
+
+	expected := []Finding{
+		Finding{Key: "qty", Check: CheckDisjointRanges, Severity: funcs.SeverityError, Message: "IsBetween(1, 100) and IsEqual(500) can never both pass"},
+		Finding{Key: "age", Check: CheckEmptyRange, Severity: funcs.SeverityError, Message: "IsBetween(10, 1) can never pass, because its lower bound is greater than its upper bound"},
+		Finding{Key: "email", Check: CheckConflictingTypes, Severity: funcs.SeverityError, Message: "IsInt(int) and String.IsEmail() can never both pass, because a value can't be both int and string"},
+		Finding{Key: "name", Check: CheckEmptyLength, Severity: funcs.SeverityError, Message: "IsLengthBetween(5, 2) can never pass, because no length is within its bounds"},
+		Finding{Key: "count", Check: CheckConflictingTypes, Severity: funcs.SeverityError, Message: "IsInt(int) and IsLength(2) can never both pass, because a value can't be both int and something with a length"},
+		Finding{Key: "country", Check: CheckRequiredDefault, Severity: funcs.SeverityWarning, Message: "default US is never used, because the key is required"},
+		Finding{Key: "tags", Check: CheckDuplicateFunc, Severity: funcs.SeverityWarning, Message: "Unique() is checked more than once"},
+		Finding{Key: "notes", Check: CheckEmptyRule, Severity: funcs.SeverityInfo, Message: "has no funcs and isn't required, so it never fails"},
+		Finding{Key: "address.zip", Check: CheckDisjointLengths, Severity: funcs.SeverityError, Message: "IsLength(5) and IsLengthBetween(6, 10) can never both pass"},
+		Finding{Key: "page", Check: CheckDisjointRanges, Severity: funcs.SeverityError, Message: "Between(1, 100) and Min(200) can never both pass"},
+		Finding{Key: "limit", Check: CheckConflictingTypes, Severity: funcs.SeverityError, Message: "Untyped(int) and String.IsInt(int) can never both pass, because a value can't be both int and string"},
+		Finding{Key: "sizes", Check: CheckConflictingTypes, Severity: funcs.SeverityError, Message: "IsType(map[string]int) and IsInt(int) can never both pass, because a value can't be both map[string]int and int"},
+	}
+
+	findings := Validator(v)
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("Findings should be\n%v\nThey are\n%v", expected, findings)
+	}
+
+	if s := findings[0].String(); s != "qty: error: IsBetween(1, 100) and IsEqual(500) can never both pass (disjoint-ranges)" {
+		t.Errorf("Finding string is %q", s)
+	}
+}
+
+func TestRecursive(t *testing.T) {
+	category, _ := validator.New([]validator.Rule{validator.Rule{Key: "name", IsRequired: true, Funcs: []funcs.Func{funcs.IsLengthBetween(1, 50)}}})
+	category.AddRule("parent", validator.Nested(category))
+
+	if findings := Validator(category); len(findings) != 0 {
+		t.Errorf("A recursive validator should have no findings. They are %v", findings)
+	}
+}
+
+func TestSeverityAndDefaults(t *testing.T) {
+	between := funcs.IsBetween(transform.None, compare.Int, 1, 100)
+
+	v, _ := validator.New([]validator.Rule{
+		validator.Rule{Key: "qty", Funcs: []funcs.Func{between, funcs.Warning(funcs.IsEqual(transform.None, compare.Int, 500))}},
+		validator.Rule{Key: "age", Funcs: []funcs.Func{funcs.Warning(funcs.And(funcs.IsBetween(transform.None, compare.Int, 10, 1)))}},
+		validator.Rule{Key: "size", Funcs: []funcs.Func{funcs.IsLength(2)}, Severity: funcs.SeverityInfo},
+		validator.Rule{Key: "email", Funcs: []funcs.Func{funcs.IsInt, funcs.String.IsEmail}, Severity: funcs.SeverityWarning},
+		validator.Rule{Key: "tags", Funcs: []funcs.Func{funcs.Unique, funcs.Warning(funcs.Unique)}},
+		validator.Rule{Key: "page", Funcs: []funcs.Func{between}, Default: 0},
+		validator.Rule{Key: "limit", Funcs: []funcs.Func{funcs.Untyped(funcs.Max(50))}, Default: 100},
+		validator.Rule{Key: "status", Funcs: []funcs.Func{funcs.Untyped(funcs.OneOf("draft", "submitted"))}, Default: "shipped"},
+		validator.Rule{Key: "zip", Funcs: []funcs.Func{funcs.IsLength(5)}, Default: "123"},
+		validator.Rule{Key: "flag", Funcs: []funcs.Func{funcs.IsBool}, Default: "yes"},
+	})
+
+	v.AddRule("size", funcs.IsLength(3))
+
+	expected := []Finding{
+		Finding{Key: "qty", Check: CheckDisjointRanges, Severity: funcs.SeverityWarning, Message: "IsBetween(1, 100) and IsEqual(500) can never both pass"},
+		Finding{Key: "age", Check: CheckEmptyRange, Severity: funcs.SeverityWarning, Message: "IsBetween(10, 1) can never pass, because its lower bound is greater than its upper bound"},
+		Finding{Key: "size", Check: CheckDisjointLengths, Severity: funcs.SeverityWarning, Message: "IsLength(2) and IsLength(3) can never both pass"},
+		Finding{Key: "email", Check: CheckConflictingTypes, Severity: funcs.SeverityWarning, Message: "IsInt(int) and String.IsEmail() can never both pass, because a value can't be both int and string"},
+		Finding{Key: "tags", Check: CheckDuplicateFunc, Severity: funcs.SeverityWarning, Message: "Unique() is checked more than once"},
+		Finding{Key: "page", Check: CheckInvalidDefault, Severity: funcs.SeverityWarning, Message: "default 0 doesn't pass IsBetween(1, 100)"},
+		Finding{Key: "limit", Check: CheckInvalidDefault, Severity: funcs.SeverityWarning, Message: "default 100 doesn't pass Max(50)"},
+		Finding{Key: "status", Check: CheckInvalidDefault, Severity: funcs.SeverityWarning, Message: "default shipped doesn't pass OneOf([draft submitted])"},
+		Finding{Key: "zip", Check: CheckInvalidDefault, Severity: funcs.SeverityWarning, Message: "default 123 doesn't pass IsLength(5)"},
+		Finding{Key: "flag", Check: CheckInvalidDefault, Severity: funcs.SeverityWarning, Message: "default yes doesn't pass IsBool(bool)"},
+	}
+
+	if findings := Validator(v); !reflect.DeepEqual(findings, expected) {
+		t.Errorf("Findings should be\n%v\nThey are\n%v", expected, findings)
+	}
+}
+
+func TestNoFindings(t *testing.T) {
+	noFindingTests := []validator.Rule{
+		validator.Rule{Key: "qty", Funcs: []funcs.Func{funcs.IsBetween(transform.None, compare.Int, 1, 100), funcs.IsEqual(transform.None, compare.Int, 100)}},
+		validator.Rule{Key: "page", Funcs: []funcs.Func{funcs.Untyped(funcs.Min(1)), funcs.Untyped(funcs.Max(1))}},
+		validator.Rule{Key: "zip", Funcs: []funcs.Func{funcs.IsLength(5), funcs.IsLengthBetween(1, 5), funcs.String.IsInt}},
+		validator.Rule{Key: "labels", Funcs: []funcs.Func{funcs.IsType(reflect.TypeOf([]string{})), funcs.Unique, funcs.IsLengthBetween(0, 3)}},
+		validator.Rule{Key: "sizes", Funcs: []funcs.Func{funcs.IsType(reflect.TypeOf(map[string]int{})), funcs.EachKey(funcs.IsLength(1))}},
+		validator.Rule{Key: "email", Funcs: []funcs.Func{funcs.Optional(funcs.String.IsEmail), funcs.IsLengthBetween(3, 254)}},
+		validator.Rule{Key: "limit", Funcs: []funcs.Func{funcs.Untyped(funcs.Between(1, 50))}, Default: 50},
+		validator.Rule{Key: "status", Funcs: []funcs.Func{funcs.Untyped(funcs.OneOf("draft", "submitted"))}, Default: "draft"},
+		validator.Rule{Key: "country", Funcs: []funcs.Func{funcs.IsLength(2)}, Default: "US"},
+		validator.Rule{Key: "coupon", Funcs: []funcs.Func{funcs.Unique, funcs.Each(funcs.Unique)}},
+		validator.Rule{Key: "plan", IsRequired: true},
+	}
+
+	for _, rule := range noFindingTests {
+		v, _ := validator.New([]validator.Rule{rule})
+		if findings := Validator(v); len(findings) != 0 {
+			t.Errorf("%s should have no findings. They are %v", rule.Key, findings)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package validator
+
+import (
+	"github.com/nmante/validator/funcs"
+)
+
+// Nested returns a Func that validates a map[string]interface{} value with a child validator. The
+// child runs with its own options, so it validates in parallel if it was created with OptionParallel.
+// Its errors are reported under the parent key, like "shipping_address.city". A child that's being
+// described already, because it's nested in itself, is described by an "enclosing" param with how
+// many levels up it is instead of a Schema
+func Nested(child *Validator) funcs.Func {
+	meta := func(path []interface{}) funcs.Meta {
+		meta := funcs.Meta{Name: "Nested", Description: "must be an object", ErrorCodes: []string{"object"}}
+		if levels := enclosing(path, child); levels > 0 {
+			meta.Params = []funcs.Param{funcs.Param{Name: "enclosing", Value: levels}}
+		} else {
+			meta.Schema = child.describe(path)
+		}
+
+		return meta
+	}
+
+	return funcs.WithMetaPathFunc(meta, func(v interface{}) (funcs.Response, error) {
+		values, ok := v.(map[string]interface{})
+		if !ok {
+			return funcs.Response{IsValid: false, Error: "must be an object"}, nil
+		}
+
+		r, err := child.Validate(values)
+		if err != nil {
+			return funcs.Response{}, err
+		}
+
+		return toFuncsResponse(r), nil
+	})
+}
+
+// EachNested returns a Func that validates every element of a slice or array of
+// map[string]interface{} values with a child validator, reporting errors like "items[2].sku"
+func EachNested(child *Validator) funcs.Func {
+	nested := Nested(child)
+	meta := func(path []interface{}) funcs.Meta {
+		element, _ := funcs.DescribePath(path, nested)
+		return funcs.Meta{Name: "EachNested", Children: []funcs.Meta{element}}
+	}
+
+	return funcs.WithMetaPathFunc(meta, funcs.Each(nested))
+}
+
+// toFuncsResponse converts a Response to a funcs.Response with an error for each of its fields.
+// Warnings and infos come after the errors
+func toFuncsResponse(r Response) funcs.Response {
+	nested := []funcs.PathError{}
+	appendFields := func(fieldErrors []FieldError, severity funcs.Severity) {
+		for _, fe := range fieldErrors {
+			path := fe.Key
+			if path == FormKey {
+				path = ""
+			}
+
+			nested = append(nested, funcs.PathError{Path: path, Error: fe.Message, Severity: severity})
+		}
+	}
+
+	appendFields(r.FieldErrors(), funcs.SeverityError)
+	errorCount := len(nested)
+	appendFields(r.FieldWarnings(), funcs.SeverityWarning)
+	appendFields(r.FieldInfos(), funcs.SeverityInfo)
+
+	if r.IsValid {
+		if len(nested) == 0 {
+			return funcs.Response{IsValid: true}
+		}
+
+		return funcs.Response{IsValid: true, Nested: nested}
+	}
+
+	if errorCount == 0 && len(nested) == 0 {
+		return funcs.Response{IsValid: false, Error: "is invalid"}
+	}
+
+	if errorCount == 0 {
+		nested = append([]funcs.PathError{funcs.PathError{Error: "is invalid"}}, nested...)
+	}
+
+	return funcs.Response{IsValid: false, Error: "has invalid fields", Nested: nested}
+}
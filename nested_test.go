@@ -0,0 +1,49 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nmante/validator/funcs"
+)
+
+func TestNested(t *testing.T) {
+	address, _ := New([]Rule{
+		Rule{Key: "street", IsRequired: true},
+		Rule{Key: "country", Funcs: []funcs.Func{funcs.IsLength(2)}},
+	}, OptionParallel(true))
+
+	item, _ := New([]Rule{
+		Rule{Key: "sku", IsRequired: true},
+		Rule{Key: "quantity", Funcs: []funcs.Func{funcs.IsInt}},
+	})
+
+	order, _ := New([]Rule{
+		Rule{Key: "shipping_address", IsRequired: true, Funcs: []funcs.Func{Nested(address)}},
+		Rule{Key: "items", Funcs: []funcs.Func{EachNested(item)}},
+	})
+
+	r, err := order.Validate(map[string]interface{}{
+		"shipping_address": map[string]interface{}{"country": "USA"},
+		"items": []interface{}{
+			map[string]interface{}{"sku": "a", "quantity": 1},
+			map[string]interface{}{"quantity": "2"},
+			"not an object",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []FieldError{
+		FieldError{Key: "shipping_address.street", Message: "is required"},
+		FieldError{Key: "shipping_address.country", Message: "Must have length 2"},
+		FieldError{Key: "items[1].sku", Message: "is required"},
+		FieldError{Key: "items[1].quantity", Message: "must be a int"},
+		FieldError{Key: "items[2]", Message: "must be an object"},
+	}
+
+	if !reflect.DeepEqual(r.FieldErrors(), expected) {
+		t.Errorf("Errors should be %+v. They are %+v", expected, r.FieldErrors())
+	}
+}
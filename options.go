@@ -16,7 +16,91 @@ func OptionParallel(isParallel bool) Option {
 			return ErrNilValidator
 		}
 
-		v.enableParallel = true
+		v.enableParallel = isParallel
+
+		return nil
+	}
+}
+
+// OptionFailFast stops running rules as soon as any key is invalid. Keys whose rules were not
+// run are listed in Response.Skipped
+func OptionFailFast(failFast bool) Option {
+	return func(v *Validator) error {
+		if v == nil {
+			return ErrNilValidator
+		}
+
+		v.failFast = failFast
+
+		return nil
+	}
+}
+
+// OptionDetailedResults reports the outcome of every Func of every rule in Response.Details
+func OptionDetailedResults(detailed bool) Option {
+	return func(v *Validator) error {
+		if v == nil {
+			return ErrNilValidator
+		}
+
+		v.detailed = detailed
+
+		return nil
+	}
+}
+
+// OptionSelfValidation turns validating values that implement Validatable or funcs.Interface on or
+// off. It's off by default, so values that happen to have a Validate method aren't checked unless
+// it's asked for
+func OptionSelfValidation(selfValidation bool) Option {
+	return func(v *Validator) error {
+		if v == nil {
+			return ErrNilValidator
+		}
+
+		v.selfValidation = selfValidation
+
+		return nil
+	}
+}
+
+// OptionKeyNormalization matches input keys to rule keys and aliases loosely, like case
+// insensitively with KeyFoldCase. Flags can be combined, like KeyFoldCase|KeySnakeCamel
+func OptionKeyNormalization(normalization KeyNormalization) Option {
+	return func(v *Validator) error {
+		if v == nil {
+			return ErrNilValidator
+		}
+
+		v.normalization = normalization
+
+		return nil
+	}
+}
+
+// OptionMapDeprecatedKeys sets Response.Values to the input with deprecated keys renamed to their
+// replacements, so handlers only need to read the current keys. A deprecated key's value is
+// validated by its replacement's rule too, with errors reported under the replacement
+func OptionMapDeprecatedKeys(mapDeprecated bool) Option {
+	return func(v *Validator) error {
+		if v == nil {
+			return ErrNilValidator
+		}
+
+		v.mapDeprecated = mapDeprecated
+
+		return nil
+	}
+}
+
+// OptionDeprecationHook calls hook for every use of a deprecated key or value
+func OptionDeprecationHook(hook DeprecationHook) Option {
+	return func(v *Validator) error {
+		if v == nil {
+			return ErrNilValidator
+		}
+
+		v.deprecationHook = hook
 
 		return nil
 	}
@@ -0,0 +1,127 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+
+	"github.com/nmante/validator/funcs"
+)
+
+var (
+	ErrNoPattern = errors.New("Pattern rule must have a Pattern or a Regexp")
+)
+
+// PatternRule applies Funcs to every input key that matches a glob Pattern (in path.Match syntax,
+// like "meta_*") or a Regexp. Keys with an exact rule only use the exact rule. MinMatches and
+// MaxMatches constrain how many input keys match, counting keys with exact rules. A MaxMatches of 0
+// means there's no maximum. Errors about the number of matches are reported under the pattern
+type PatternRule struct {
+	Pattern        string
+	Regexp         *regexp.Regexp
+	Funcs          []funcs.Func
+	MinMatches     int
+	MaxMatches     int
+	EnableParallel bool
+	Bail           bool
+	Severity       funcs.Severity
+}
+
+// AddPatternRule adds a pattern rule to the validator
+func (v *Validator) AddPatternRule(rule PatternRule) error {
+	if rule.Pattern == "" && rule.Regexp == nil {
+		return ErrNoPattern
+	}
+
+	if rule.Pattern != "" {
+		if _, err := path.Match(rule.Pattern, ""); err != nil {
+			return fmt.Errorf("%s: %w", rule.Pattern, err)
+		}
+	}
+
+	v.patternRules = append(v.patternRules, rule)
+	return nil
+}
+
+// PatternRules returns the pattern rules for this validator object in the order they were added
+func (v *Validator) PatternRules() []PatternRule {
+	rules := make([]PatternRule, len(v.patternRules))
+	copy(rules, v.patternRules)
+
+	return rules
+}
+
+// Name returns the rule's pattern, or its regexp if it has no pattern
+func (p PatternRule) Name() string {
+	if p.Pattern != "" {
+		return p.Pattern
+	}
+
+	return p.Regexp.String()
+}
+
+// Matches checks if a key matches the rule's pattern or regexp
+func (p PatternRule) Matches(key string) bool {
+	if p.Pattern != "" {
+		if ok, _ := path.Match(p.Pattern, key); ok {
+			return true
+		}
+	}
+
+	return p.Regexp != nil && p.Regexp.MatchString(key)
+}
+
+// patternRuns returns a run for each pattern rule matching an input key without an exact rule
+func (v *Validator) patternRuns(values map[string]interface{}) []*ruleRun {
+	runs := []*ruleRun{}
+	if len(v.patternRules) == 0 {
+		return runs
+	}
+
+	keys := sortedKeys(values)
+
+	for _, pr := range v.patternRules {
+		for _, key := range keys {
+			if _, ok := v.index[key]; ok || !pr.Matches(key) {
+				continue
+			}
+
+			rule := Rule{Key: key, Funcs: pr.Funcs, EnableParallel: pr.EnableParallel, Bail: pr.Bail, Severity: pr.Severity}
+			runs = append(runs, &ruleRun{rule: rule, value: values[key], present: true})
+		}
+	}
+
+	return runs
+}
+
+// validatePatternMatches checks how many input keys match each pattern rule
+func (v *Validator) validatePatternMatches(values map[string]interface{}, response *Response) {
+	for _, pr := range v.patternRules {
+		matches := 0
+		for key := range values {
+			if pr.Matches(key) {
+				matches++
+			}
+		}
+
+		if matches < pr.MinMatches {
+			response.AddError(pr.Name(), fmt.Sprintf("at least %d keys must match", pr.MinMatches))
+		}
+
+		if pr.MaxMatches > 0 && matches > pr.MaxMatches {
+			response.AddError(pr.Name(), fmt.Sprintf("at most %d keys may match", pr.MaxMatches))
+		}
+	}
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
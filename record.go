@@ -0,0 +1,55 @@
+package validator
+
+// FormKey is the key for errors that apply to a whole record rather than to a single key
+const FormKey = "_form"
+
+// RecordFunc validates a whole record, so it can check invariants that span many keys. Errors can be
+// added to the returned Response under any key, or under FormKey. A record rule only fails through
+// the errors it adds, so returning the zero Response and no error passes
+type RecordFunc func(values map[string]interface{}) (Response, error)
+
+type recordRule struct {
+	f           RecordFunc
+	onlyIfValid bool
+}
+
+// AddRecordRule adds a RecordFunc to the validator. Record rules run after all of the per key rules,
+// in the order they were added. If onlyIfValid is true, the rule is skipped when a per key rule failed
+func (v *Validator) AddRecordRule(f RecordFunc, onlyIfValid bool) *Validator {
+	v.recordRules = append(v.recordRules, recordRule{f: f, onlyIfValid: onlyIfValid})
+	return v
+}
+
+// validateRecord runs the record rules, adding their errors to response
+func (v *Validator) validateRecord(values map[string]interface{}, response *Response) error {
+	keysValid := response.IsValid
+
+	for _, rule := range v.recordRules {
+		if !keysValid && rule.onlyIfValid {
+			continue
+		}
+
+		if v.failFast && !response.IsValid {
+			return nil
+		}
+
+		r, err := rule.f(values)
+		if err != nil {
+			return err
+		}
+
+		for _, fe := range r.FieldErrors() {
+			response.AddError(fe.Key, fe.Message)
+		}
+
+		for _, fe := range r.FieldWarnings() {
+			response.AddWarning(fe.Key, fe.Message)
+		}
+
+		for _, fe := range r.FieldInfos() {
+			response.AddInfo(fe.Key, fe.Message)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,342 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/nmante/validator/funcs"
+)
+
+const (
+	DefaultMaxDepth = 32
+)
+
+var (
+	ErrUndefinedValidator = errors.New("Validator is not defined")
+	ErrMaxDepth           = errors.New("Value is nested too deeply")
+	ErrCyclicValue        = errors.New("Value contains a cycle")
+)
+
+// Registry holds named validators that can reference each other, and themselves, with Ref. This
+// lets recursive structures like comment threads be validated. Before a referenced validator runs,
+// the value is checked for cycles and for nesting deeper than the registry's maximum depth, so
+// validation always terminates. The value is checked once per call, by Validate or by the outermost
+// Ref, and depth is measured from there
+type Registry struct {
+	mu         sync.RWMutex
+	validators map[string]*Validator
+	maxDepth   int
+	// scopes are the values being validated by each Validate call or outermost Ref, so the Refs
+	// nested in them don't check them again
+	scopesMu sync.Mutex
+	scopes   map[*checkScope]bool
+}
+
+// checkScope holds the maps checked by one Validate call or outermost Ref
+type checkScope struct {
+	maps map[uintptr]bool
+}
+
+type RegistryOption func(*Registry) error
+
+// RegistryOptionMaxDepth sets how deeply values can be nested. It defaults to DefaultMaxDepth
+func RegistryOptionMaxDepth(maxDepth int) RegistryOption {
+	return func(r *Registry) error {
+		if maxDepth < 1 {
+			return errors.New("Max depth must be at least 1")
+		}
+
+		r.maxDepth = maxDepth
+
+		return nil
+	}
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry(options ...RegistryOption) (*Registry, error) {
+	r := &Registry{
+		validators: map[string]*Validator{},
+		maxDepth:   DefaultMaxDepth,
+		scopes:     map[*checkScope]bool{},
+	}
+
+	for _, option := range options {
+		if err := option(r); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// Define adds a named validator to the registry, replacing any validator with the same name
+func (r *Registry) Define(name string, v *Validator) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.validators[name] = v
+	return r
+}
+
+// Get returns a named validator
+func (r *Registry) Get(name string) (*Validator, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v, ok := r.validators[name]
+	return v, ok
+}
+
+// Names returns the names of the registry's validators, sorted
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := []string{}
+	for name := range r.validators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Validate validates values with a named validator
+func (r *Registry) Validate(name string, values map[string]interface{}) (Response, error) {
+	v, err := r.lookup(name)
+	if err != nil {
+		return Response{}, err
+	}
+
+	// values are always checked from here, even if another call is validating them
+	release, err := r.check(values)
+	if err != nil {
+		return Response{}, err
+	}
+	defer release()
+
+	return v.Validate(values)
+}
+
+// Ref returns a Func that validates a map[string]interface{} value with a named validator, like
+// Nested. The name is looked up when the Func runs, so a validator can reference validators that
+// are defined later, or itself
+func (r *Registry) Ref(name string) funcs.Func {
+	// The referenced validator isn't described, since it can reference itself
+	meta := funcs.Meta{Name: "Ref", Params: []funcs.Param{funcs.Param{Name: "name", Value: name}}}
+
+	return funcs.WithMeta(meta, func(v interface{}) (funcs.Response, error) {
+		child, err := r.lookup(name)
+		if err != nil {
+			return funcs.Response{}, err
+		}
+
+		release, err := r.guard(v)
+		if err != nil {
+			return funcs.Response{}, err
+		}
+		defer release()
+
+		return Nested(child)(v)
+	})
+}
+
+// EachRef returns a Func that validates every element of a slice or array with a named validator
+func (r *Registry) EachRef(name string) funcs.Func {
+	ref := r.Ref(name)
+	element, _ := funcs.Describe(ref)
+
+	return funcs.WithMeta(funcs.Meta{Name: "EachRef", Children: []funcs.Meta{element}}, funcs.Each(ref))
+}
+
+func (r *Registry) lookup(name string) (*Validator, error) {
+	v, ok := r.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUndefinedValidator, name)
+	}
+
+	return v, nil
+}
+
+// guard checks a value for cycles and depth, unless it's inside a value an enclosing Validate or Ref
+// already checked. release must be called once the value is validated
+func (r *Registry) guard(v interface{}) (release func(), err error) {
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Map && !value.IsNil() && r.isChecked(value.Pointer()) {
+		return func() {}, nil
+	}
+
+	return r.check(v)
+}
+
+// check checks a value for cycles and depth in a scope of its own, which release closes
+func (r *Registry) check(v interface{}) (release func(), err error) {
+	c := newValueChecker(r.maxDepth)
+	if err := c.check(reflect.ValueOf(v), 0, ""); err != nil {
+		return nil, err
+	}
+
+	scope := &checkScope{maps: map[uintptr]bool{}}
+	for _, pointer := range c.maps {
+		scope.maps[pointer] = true
+	}
+
+	r.scopesMu.Lock()
+	r.scopes[scope] = true
+	r.scopesMu.Unlock()
+
+	return func() {
+		r.scopesMu.Lock()
+		defer r.scopesMu.Unlock()
+
+		delete(r.scopes, scope)
+	}, nil
+}
+
+func (r *Registry) isChecked(pointer uintptr) bool {
+	r.scopesMu.Lock()
+	defer r.scopesMu.Unlock()
+
+	for scope := range r.scopes {
+		if scope.maps[pointer] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// visit identifies a map, slice or pointer on the path being checked
+type visit struct {
+	pointer uintptr
+	_type   reflect.Type
+	length  int
+}
+
+type valueChecker struct {
+	maxDepth int
+	// path holds the references between the root value and the value being checked. Values can be
+	// shared by different branches, but a reference to one on the path is a cycle
+	path map[visit]bool
+	// maps are the maps that were checked
+	maps []uintptr
+}
+
+func newValueChecker(maxDepth int) *valueChecker {
+	return &valueChecker{maxDepth: maxDepth, path: map[visit]bool{}}
+}
+
+func (c *valueChecker) check(value reflect.Value, depth int, path string) error {
+	switch value.Kind() {
+	case reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+
+		return c.check(value.Elem(), depth, path)
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil
+		}
+
+		return c.enter(value, path, func() error {
+			return c.check(value.Elem(), depth, path)
+		})
+	case reflect.Map:
+		if value.IsNil() {
+			return nil
+		}
+
+		if err := c.deeper(depth, path); err != nil {
+			return err
+		}
+
+		c.maps = append(c.maps, value.Pointer())
+		return c.enter(value, path, func() error {
+			iter := value.MapRange()
+			for iter.Next() {
+				if err := c.check(iter.Value(), depth+1, fmt.Sprintf("%s[%v]", path, iter.Key())); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	case reflect.Slice:
+		if value.IsNil() {
+			return nil
+		}
+
+		if err := c.deeper(depth, path); err != nil {
+			return err
+		}
+
+		return c.enter(value, path, func() error {
+			return c.checkElements(value, depth, path)
+		})
+	case reflect.Array:
+		if err := c.deeper(depth, path); err != nil {
+			return err
+		}
+
+		return c.checkElements(value, depth, path)
+	case reflect.Struct:
+		if err := c.deeper(depth, path); err != nil {
+			return err
+		}
+
+		for i := 0; i < value.NumField(); i++ {
+			if err := c.check(value.Field(i), depth+1, path+"."+value.Type().Field(i).Name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *valueChecker) checkElements(value reflect.Value, depth int, path string) error {
+	for i := 0; i < value.Len(); i++ {
+		if err := c.check(value.Index(i), depth+1, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *valueChecker) deeper(depth int, path string) error {
+	if depth >= c.maxDepth {
+		return fmt.Errorf("%w: more than %d levels at %s", ErrMaxDepth, c.maxDepth, displayPath(path))
+	}
+
+	return nil
+}
+
+// enter checks a reference for a cycle, then runs check with the reference on the path
+func (c *valueChecker) enter(value reflect.Value, path string, check func() error) error {
+	v := visit{pointer: value.Pointer(), _type: value.Type()}
+	if value.Kind() == reflect.Slice {
+		v.length = value.Len()
+	}
+
+	if c.path[v] {
+		return fmt.Errorf("%w at %s", ErrCyclicValue, displayPath(path))
+	}
+
+	c.path[v] = true
+	defer delete(c.path, v)
+
+	return check()
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "the root"
+	}
+
+	return path
+}
@@ -0,0 +1,139 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/nmante/validator/funcs"
+)
+
+func newCommentRegistry(t *testing.T, options ...RegistryOption) *Registry {
+	registry, err := NewRegistry(options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comment, _ := New([]Rule{
+		Rule{Key: "body", IsRequired: true, Funcs: []funcs.Func{funcs.IsLengthBetween(1, 140)}},
+		Rule{Key: "replies", Funcs: []funcs.Func{registry.EachRef("comment")}},
+		Rule{Key: "author", Funcs: []funcs.Func{registry.Ref("user")}},
+	})
+
+	user, _ := New([]Rule{Rule{Key: "name", IsRequired: true}})
+
+	return registry.Define("comment", comment).Define("user", user)
+}
+
+func TestRegistryRefs(t *testing.T) {
+	registry := newCommentRegistry(t)
+
+	r, err := registry.Validate("comment", map[string]interface{}{
+		"body": "first",
+		"replies": []interface{}{
+			map[string]interface{}{"body": "second", "author": map[string]interface{}{}},
+			map[string]interface{}{
+				"body": "third",
+				"replies": []interface{}{
+					map[string]interface{}{"body": ""},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []FieldError{
+		FieldError{Key: "replies[0].author.name", Message: "is required"},
+		FieldError{Key: "replies[1].replies[0].body", Message: "Must be between length 1 and 140"},
+	}
+
+	if !reflect.DeepEqual(r.FieldErrors(), expected) {
+		t.Errorf("Errors should be %+v. They are %+v", expected, r.FieldErrors())
+	}
+
+	if _, err := registry.Validate("post", nil); !errors.Is(err, ErrUndefinedValidator) {
+		t.Errorf("Undefined validators should be an error. Error: %v", err)
+	}
+
+	if names := registry.Names(); !reflect.DeepEqual(names, []string{"comment", "user"}) {
+		t.Errorf("Names should be sorted. They are %v", names)
+	}
+}
+
+func TestRegistryGuards(t *testing.T) {
+	registry := newCommentRegistry(t, RegistryOptionMaxDepth(4))
+
+	deep := map[string]interface{}{"body": "leaf"}
+	for i := 0; i < 3; i++ {
+		deep = map[string]interface{}{"body": "node", "replies": []interface{}{deep}}
+	}
+
+	if _, err := registry.Validate("comment", deep); !errors.Is(err, ErrMaxDepth) {
+		t.Errorf("Deep values should be an error. Error: %v", err)
+	}
+
+	cyclic := map[string]interface{}{"body": "loop"}
+	cyclic["replies"] = []interface{}{cyclic}
+
+	if _, err := registry.Validate("comment", cyclic); !errors.Is(err, ErrCyclicValue) {
+		t.Errorf("Cyclic values should be an error. Error: %v", err)
+	}
+
+	shared := map[string]interface{}{"body": "shared"}
+	r, err := registry.Validate("comment", map[string]interface{}{
+		"body":    "root",
+		"replies": []interface{}{shared, shared},
+	})
+	if err != nil || !r.IsValid {
+		t.Errorf("Values shared by branches aren't cycles. Error: %v, %+v", err, r.Errors)
+	}
+
+	// a Ref in a validator outside the registry checks the value it's given, and its nested Refs
+	// don't check it again
+	outside, _ := New([]Rule{Rule{Key: "thread", Funcs: []funcs.Func{registry.Ref("comment")}}})
+	if _, err := outside.Validate(map[string]interface{}{"thread": cyclic}); !errors.Is(err, ErrCyclicValue) {
+		t.Errorf("Cyclic values under a Ref should be an error. Error: %v", err)
+	}
+
+	if _, err := outside.Validate(map[string]interface{}{"thread": deep}); !errors.Is(err, ErrMaxDepth) {
+		t.Errorf("Deep values under a Ref should be an error. Error: %v", err)
+	}
+
+	if r, err := outside.Validate(map[string]interface{}{"thread": shared}); err != nil || !r.IsValid {
+		t.Errorf("Shallow values under a Ref should be valid. Error: %v, %+v", err, r.Errors)
+	}
+
+	// each Validate call checks its values, even ones another call is still validating
+	reply := map[string]interface{}{"body": "reply"}
+	release, err := registry.check(map[string]interface{}{"body": "root", "replies": []interface{}{reply}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reply["replies"] = []interface{}{reply}
+	if _, err := registry.Validate("comment", reply); !errors.Is(err, ErrCyclicValue) {
+		t.Errorf("Values checked by another call should be checked again. Error: %v", err)
+	}
+	release()
+
+	if len(registry.scopes) != 0 {
+		t.Errorf("Checked values should be released after validation. There are %d", len(registry.scopes))
+	}
+
+	type node struct {
+		Next *node
+	}
+	n := &node{}
+	n.Next = n
+
+	if err := checkValue(reflect.ValueOf(n), DefaultMaxDepth); !errors.Is(err, ErrCyclicValue) {
+		t.Errorf("Pointer loops should be an error. Error: %v", err)
+	}
+}
+
+// checkValue checks that a value has no cycles and isn't nested more than maxDepth levels deep
+func checkValue(value reflect.Value, maxDepth int) error {
+	return newValueChecker(maxDepth).check(value, 0, "")
+}
@@ -0,0 +1,167 @@
+package validator
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/nmante/validator/funcs"
+)
+
+// Response contains a bool for if all rules are valid, as well as error messages for invalid rules.
+// Skipped lists the keys whose rules weren't run because validation failed fast, and Details holds
+// the response of each detailed rule that ran. Warnings and Infos hold the messages of checks with
+// those severities. They don't make the response invalid
+type Response struct {
+	Errors   map[string][]string
+	IsValid  bool
+	Skipped  []string
+	Details  []RuleResponse
+	Warnings map[string][]string
+	Infos    map[string][]string
+	// Values is the input with deprecated keys renamed to their replacements. It's only set with
+	// OptionMapDeprecatedKeys
+	Values map[string]interface{}
+	// order records the order keys were first added to Errors in, and warningOrder and infoOrder
+	// the same for Warnings and Infos
+	order        []string
+	warningOrder []string
+	infoOrder    []string
+}
+
+// FieldError is a single validation error message for a key
+type FieldError struct {
+	Key     string
+	Message string
+}
+
+// AddError appends validation error messages for a key and marks the response as invalid
+func (r *Response) AddError(key string, messages ...string) {
+	addMessages(&r.Errors, &r.order, key, messages)
+	r.IsValid = false
+}
+
+// AddWarning appends warning messages for a key. The response stays valid
+func (r *Response) AddWarning(key string, messages ...string) {
+	addMessages(&r.Warnings, &r.warningOrder, key, messages)
+}
+
+// AddInfo appends informational messages for a key. The response stays valid
+func (r *Response) AddInfo(key string, messages ...string) {
+	addMessages(&r.Infos, &r.infoOrder, key, messages)
+}
+
+// add appends messages for a key to the errors, warnings or infos, depending on severity
+func (r *Response) add(severity funcs.Severity, key string, messages ...string) {
+	switch severity {
+	case funcs.SeverityWarning:
+		r.AddWarning(key, messages...)
+	case funcs.SeverityInfo:
+		r.AddInfo(key, messages...)
+	default:
+		r.AddError(key, messages...)
+	}
+}
+
+func addMessages(m *map[string][]string, order *[]string, key string, messages []string) {
+	if *m == nil {
+		*m = map[string][]string{}
+	}
+
+	if _, ok := (*m)[key]; !ok {
+		*order = append(*order, key)
+	}
+
+	(*m)[key] = append((*m)[key], messages...)
+}
+
+// FieldErrors returns every validation error in the order they were added. For the validator's own
+// responses this is rule registration order, then func order within a rule. Keys written to Errors
+// directly come last, sorted by key
+func (r Response) FieldErrors() []FieldError {
+	return fieldMessages(r.Errors, r.order)
+}
+
+// FieldWarnings returns every warning in the order they were added, like FieldErrors
+func (r Response) FieldWarnings() []FieldError {
+	return fieldMessages(r.Warnings, r.warningOrder)
+}
+
+// FieldInfos returns every informational message in the order they were added, like FieldErrors
+func (r Response) FieldInfos() []FieldError {
+	return fieldMessages(r.Infos, r.infoOrder)
+}
+
+// fieldMessages flattens messages keyed by key, in order, followed by the remaining keys sorted
+func fieldMessages(m map[string][]string, order []string) []FieldError {
+	fieldErrors := []FieldError{}
+	seen := map[string]bool{}
+
+	appendKey := func(key string) {
+		messages, ok := m[key]
+		if !ok || seen[key] {
+			return
+		}
+
+		seen[key] = true
+		for _, message := range messages {
+			fieldErrors = append(fieldErrors, FieldError{Key: key, Message: message})
+		}
+	}
+
+	for _, key := range order {
+		appendKey(key)
+	}
+
+	rest := []string{}
+	for key := range m {
+		if !seen[key] {
+			rest = append(rest, key)
+		}
+	}
+
+	sort.Strings(rest)
+	for _, key := range rest {
+		appendKey(key)
+	}
+
+	return fieldErrors
+}
+
+// Merge adds every error, warning and info in other to r, with keys prefixed by prefix. Messages
+// under FormKey are added under prefix itself, so a nested record's form level errors stay with the
+// nested key
+func (r *Response) Merge(prefix string, other Response) {
+	for _, fe := range other.FieldErrors() {
+		r.AddError(joinKey(prefix, fe.Key), fe.Message)
+	}
+
+	for _, fe := range other.FieldWarnings() {
+		r.AddWarning(joinKey(prefix, fe.Key), fe.Message)
+	}
+
+	for _, fe := range other.FieldInfos() {
+		r.AddInfo(joinKey(prefix, fe.Key), fe.Message)
+	}
+
+	for _, key := range other.Skipped {
+		r.Skipped = append(r.Skipped, joinKey(prefix, key))
+	}
+
+	if !other.IsValid && len(other.Errors) == 0 {
+		r.AddError(prefix, "is invalid")
+	}
+}
+
+// joinKey joins a key path like "address" with a child key like "city" or "[2]"
+func joinKey(prefix string, key string) string {
+	switch {
+	case prefix == "":
+		return key
+	case key == "" || key == FormKey:
+		return prefix
+	case strings.HasPrefix(key, "["):
+		return prefix + key
+	default:
+		return prefix + "." + key
+	}
+}
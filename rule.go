@@ -1,6 +1,8 @@
 package validator
 
 import (
+	"time"
+
 	"github.com/nmante/validator/funcs"
 )
 
@@ -10,6 +12,32 @@ type Rule struct {
 	Key            string
 	IsRequired     bool
 	EnableParallel bool
+	// Bail stops running the rule's Funcs after the first one that fails. When EnableParallel
+	// is set, Funcs that haven't started yet are skipped
+	Bail bool
+	// Groups limits the rule to the scenarios passed to ValidateFor, like "create" or "admin". Rules
+	// without groups apply to every scenario. Validate runs every rule
+	Groups []string
+	// RequiredFor overrides IsRequired for scenarios passed to ValidateFor
+	RequiredFor map[string]bool
+	// Aliases are other keys the rule's value can be sent under. Values are reported under Key
+	Aliases []string
+	// Detailed adds the outcome of each of the rule's Funcs to its RuleResponse
+	Detailed bool
+	// Severity is the severity of the rule's failures. Funcs can report warnings and infos in an
+	// error rule with funcs.WithSeverity, but every failure of a warning or info rule has its
+	// severity, including a missing required key
+	Severity funcs.Severity
+	// Deprecation marks the key as deprecated, adding a warning when it's sent
+	Deprecation *Deprecation
+	// DeprecatedValues maps deprecated values of the key to the warning to add when they're sent. An
+	// empty warning is replaced with "<value> is deprecated"
+	DeprecatedValues map[interface{}]string
+	// Description, Default and Examples document the key. They're returned by Validator.Describe.
+	// Default is the value the key takes when it isn't sent; it isn't applied by the validator
+	Description string
+	Default     interface{}
+	Examples    []interface{}
 }
 
 // RuleResponse is the result returned from executing all of the Funcs in a Rule. It includes
@@ -19,12 +47,32 @@ type RuleResponse struct {
 	Key              string
 	IsValid          bool
 	ValidationErrors []string
+	// NestedErrors are errors for parts of the value, like the elements of a slice
+	NestedErrors []funcs.PathError
+	// Warnings and Infos are the messages of failures with those severities. Their Path is empty
+	// for the value itself
+	Warnings []funcs.PathError
+	Infos    []funcs.PathError
+	// Results is only populated for detailed rules
+	Results []FuncResult
+}
+
+// FuncResult is the outcome of running a single Func in a Rule. Index is the Func's position in
+// Rule.Funcs, and Name is set if the Func was registered or wrapped with funcs.Named
+type FuncResult struct {
+	Index    int
+	Name     string
+	IsValid  bool
+	Skipped  bool
+	Message  string
+	Severity funcs.Severity
+	Duration time.Duration
 }
 
-func (r Rule) createFuncJobs(value interface{}) ([]Job, error) {
+func (r Rule) createFuncJobs(value interface{}, c *canceler) ([]Job, error) {
 	jobs := []Job{}
 	for _, f := range r.Funcs {
-		job, err := NewFuncJob(value, f)
+		job, err := NewFuncJob(value, f, withFuncCanceler(c))
 		if err != nil {
 			return jobs, err
 		}
@@ -37,9 +85,18 @@ func (r Rule) createFuncJobs(value interface{}) ([]Job, error) {
 
 func (r Rule) execute(value interface{}) (RuleResponse, error) {
 	errors := []string{}
+	nested := []funcs.PathError{}
+	var results []FuncResult
+	var warnings, infos []funcs.PathError
 	isValid := true
 
-	jobs, err := r.createFuncJobs(value)
+	// Bail only stops at errors, and a warning or info rule has none
+	var c *canceler
+	if r.Bail && r.Severity == funcs.SeverityError {
+		c = newCanceler()
+	}
+
+	jobs, err := r.createFuncJobs(value, c)
 	if err != nil {
 		return RuleResponse{}, err
 	}
@@ -52,29 +109,87 @@ func (r Rule) execute(value interface{}) (RuleResponse, error) {
 		pool.Run()
 	}
 
-	for _, job := range jobs {
+	for i, job := range jobs {
 		j, ok := job.(*FuncJob)
 		if !ok {
 			return RuleResponse{}, ErrMustBeFuncJob
 		}
 
 		if !r.EnableParallel {
-			response, err := j.validatorFunc(value)
-			j.Err = err
-			j.Result = response
+			j.run()
+		}
+
+		severity := r.severityOf(j.Result.Severity)
+
+		if r.Detailed {
+			name, _ := funcs.NameOf(r.Funcs[i])
+			results = append(results, FuncResult{
+				Index:    i,
+				Name:     name,
+				IsValid:  !j.Skipped && j.Err == nil && j.Result.IsValid,
+				Skipped:  j.Skipped,
+				Message:  j.Result.Error,
+				Severity: severity,
+				Duration: j.Duration,
+			})
+		}
+
+		if j.Skipped {
+			continue
 		}
 
 		if j.Err != nil {
 			return RuleResponse{}, j.Err
 		}
 
-		errors = append(errors, j.Result.Error)
-		isValid = isValid && j.Result.IsValid
+		failures := j.Result.Nested
+		if !j.Result.IsValid && len(failures) == 0 {
+			failures = []funcs.PathError{funcs.PathError{Error: j.Result.Error}}
+		}
+
+		hasErrors := false
+		for _, pe := range failures {
+			pe.Severity = r.severityOf(j.Result.SeverityOf(pe))
+
+			switch pe.Severity {
+			case funcs.SeverityWarning:
+				warnings = append(warnings, pe)
+			case funcs.SeverityInfo:
+				infos = append(infos, pe)
+			default:
+				// Parallel funcs may fail concurrently, so only the first failure in order is reported
+				if r.Bail && !isValid {
+					continue
+				}
+
+				hasErrors = true
+				if len(j.Result.Nested) > 0 {
+					nested = append(nested, pe)
+				} else {
+					errors = append(errors, pe.Error)
+				}
+			}
+		}
+
+		isValid = isValid && !hasErrors
 	}
 
 	return RuleResponse{
 		Key:              r.Key,
 		ValidationErrors: errors,
+		NestedErrors:     nested,
+		Warnings:         warnings,
+		Infos:            infos,
 		IsValid:          isValid,
+		Results:          results,
 	}, nil
 }
+
+// severityOf returns the severity of a failure in the rule
+func (r Rule) severityOf(severity funcs.Severity) funcs.Severity {
+	if r.Severity != funcs.SeverityError {
+		return r.Severity
+	}
+
+	return severity
+}
@@ -0,0 +1,54 @@
+package validator
+
+// ValidateFor validates values with the rules that apply to a scenario, like "create" or "update",
+// with IsRequired overridden by each rule's RequiredFor
+func (v *Validator) ValidateFor(scenario string, values map[string]interface{}) (Response, error) {
+	return v.forScenario(scenario).Validate(values)
+}
+
+// RulesFor returns the rules that apply to a scenario in registration order, with IsRequired
+// overridden by each rule's RequiredFor
+func (v *Validator) RulesFor(scenario string) []Rule {
+	rules := []Rule{}
+	for _, rule := range v.rules {
+		if !rule.appliesTo(scenario) {
+			continue
+		}
+
+		if isRequired, ok := rule.RequiredFor[scenario]; ok {
+			rule.IsRequired = isRequired
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// appliesTo checks if a rule is in a scenario's group. Rules without groups apply to every scenario
+func (r Rule) appliesTo(scenario string) bool {
+	if len(r.Groups) == 0 {
+		return true
+	}
+
+	for _, group := range r.Groups {
+		if group == scenario {
+			return true
+		}
+	}
+
+	return false
+}
+
+// forScenario returns a copy of the validator with only the rules for a scenario
+func (v *Validator) forScenario(scenario string) *Validator {
+	scoped := *v
+	scoped.rules = v.RulesFor(scenario)
+	scoped.index = map[string]int{}
+
+	for i, rule := range scoped.rules {
+		scoped.index[rule.Key] = i
+	}
+
+	return &scoped
+}
@@ -0,0 +1,101 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/nmante/validator/funcs"
+)
+
+func TestScenarios(t *testing.T) {
+	validator, _ := New([]Rule{
+		Rule{Key: "id", Groups: []string{"update", "admin"}, RequiredFor: map[string]bool{"update": true}},
+		Rule{Key: "name", IsRequired: true, RequiredFor: map[string]bool{"update": false}, Funcs: []funcs.Func{funcs.IsLengthBetween(1, 10)}},
+		Rule{Key: "role", Groups: []string{"admin"}, Funcs: []funcs.Func{funcs.Untyped(funcs.OneOf("user", "admin"))}},
+	})
+
+	keys := func(rules []Rule) []string {
+		keys := []string{}
+		for _, rule := range rules {
+			keys = append(keys, rule.Key)
+		}
+
+		return keys
+	}
+
+	if k := keys(validator.RulesFor("create")); !reflect.DeepEqual(k, []string{"name"}) {
+		t.Errorf("Only name should apply on create. Rules: %v", k)
+	}
+
+	if k := keys(validator.RulesFor("admin")); !reflect.DeepEqual(k, []string{"id", "name", "role"}) {
+		t.Errorf("Every rule should apply on admin. Rules: %v", k)
+	}
+
+	scenarioTests := []struct {
+		scenario string
+		values   map[string]interface{}
+		expected []FieldError
+	}{
+		{scenario: "create", values: map[string]interface{}{"role": "root"}, expected: []FieldError{
+			FieldError{Key: "name", Message: "is required"},
+		}},
+		{scenario: "update", values: map[string]interface{}{}, expected: []FieldError{
+			FieldError{Key: "id", Message: "is required"},
+		}},
+		{scenario: "admin", values: map[string]interface{}{"name": "a", "role": "root"}, expected: []FieldError{
+			FieldError{Key: "role", Message: "must be one of user, admin"},
+		}},
+	}
+
+	for _, test := range scenarioTests {
+		r, err := validator.ValidateFor(test.scenario, test.values)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(r.FieldErrors(), test.expected) {
+			t.Errorf("%s: errors should be %+v. They are %+v", test.scenario, test.expected, r.FieldErrors())
+		}
+	}
+}
+
+func TestDuplicateRules(t *testing.T) {
+	mergeTests := []struct {
+		rules     []Rule
+		conflicts bool
+	}{
+		{rules: []Rule{
+			Rule{Key: "id", IsRequired: true, Groups: []string{"update"}, Funcs: []funcs.Func{funcs.IsInt}},
+			Rule{Key: "id", Funcs: []funcs.Func{funcs.IsInt}, Aliases: []string{"ID"}},
+		}},
+		{rules: []Rule{
+			Rule{Key: "id", Groups: []string{"update"}, Funcs: []funcs.Func{funcs.IsInt}},
+			Rule{Key: "id", Groups: []string{"update"}, Funcs: []funcs.Func{funcs.IsInt}},
+		}},
+		{rules: []Rule{
+			Rule{Key: "id", Funcs: []funcs.Func{funcs.IsInt}},
+			Rule{Key: "id", Groups: []string{"admin"}, Funcs: []funcs.Func{funcs.IsBool}},
+		}, conflicts: true},
+		{rules: []Rule{
+			Rule{Key: "id", RequiredFor: map[string]bool{"update": true}},
+			Rule{Key: "id", RequiredFor: map[string]bool{"create": true}},
+		}, conflicts: true},
+		{rules: []Rule{
+			Rule{Key: "id"},
+			Rule{Key: "id", IsRequired: true, Severity: funcs.SeverityWarning, Description: "The id"},
+		}},
+	}
+
+	for i, test := range mergeTests {
+		_, err := New(test.rules)
+		if conflicts := errors.Is(err, ErrConflictingRules); conflicts != test.conflicts {
+			t.Errorf("Test %d: rules should conflict: %t. Error is %v", i, test.conflicts, err)
+		}
+	}
+
+	v, _ := New(mergeTests[0].rules)
+	if rule := v.Rules()["id"]; len(rule.Funcs) != 2 || !rule.IsRequired || !reflect.DeepEqual(rule.Aliases, []string{"ID"}) {
+		t.Errorf("Rules should be merged into the first. The rule is %+v", rule)
+	}
+}
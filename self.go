@@ -0,0 +1,52 @@
+package validator
+
+import (
+	"github.com/nmante/validator/funcs"
+)
+
+// Validatable is implemented by values that validate themselves and report errors for their own
+// fields, like the Validate methods written by the gen command. Their errors are merged under the
+// value's key, so a "city" error on an "address" value is reported as "address.city"
+type Validatable interface {
+	Validate() (Response, error)
+}
+
+// selfValidate validates a value that implements Validatable or funcs.Interface, adding its errors
+// under key. Other values are ignored
+func selfValidate(key string, value interface{}, response *Response) error {
+	switch v := value.(type) {
+	case Validatable:
+		r, err := v.Validate()
+		if err != nil {
+			return err
+		}
+
+		response.Merge(key, r)
+	case funcs.Interface:
+		r, err := v.Validate()
+		if err != nil {
+			return err
+		}
+
+		if !r.IsValid {
+			response.add(r.Severity, key, r.Error)
+		}
+	}
+
+	return nil
+}
+
+// selfValidateUnruled self validates the values that don't have a rule, in key order
+func (v *Validator) selfValidateUnruled(values map[string]interface{}, response *Response) error {
+	for _, key := range sortedKeys(values) {
+		if _, ok := v.index[key]; ok {
+			continue
+		}
+
+		if err := selfValidate(key, values[key], response); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
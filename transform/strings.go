@@ -10,6 +10,7 @@ var (
 )
 
 var (
+	None            = none{}
 	StringToInt     = stringToInt{}
 	StringToFloat32 = stringToFloat32{}
 	StringToFloat64 = stringToFloat64{}
@@ -17,6 +18,13 @@ var (
 	StringToBool    = stringToBool{}
 )
 
+type none struct{}
+
+// Transform returns the value unchanged
+func (n none) Transform(v interface{}) (interface{}, error) {
+	return v, nil
+}
+
 type stringToInt struct{}
 
 // Transform converts a string to an integer
@@ -10,6 +10,7 @@ var _int16 int16
 var _int32 int32
 var _int64 int64
 var _bool bool
+var _string string
 
 var _uint uint
 var _uint8 uint8
@@ -35,6 +36,8 @@ var (
 	Int64 = reflect.TypeOf(_int64)
 	Bool  = reflect.TypeOf(_bool)
 
+	String = reflect.TypeOf(_string)
+
 	Uint    = reflect.TypeOf(_uint)
 	Uint8   = reflect.TypeOf(_uint8)
 	Uint16  = reflect.TypeOf(_uint16)
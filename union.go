@@ -0,0 +1,81 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nmante/validator/funcs"
+)
+
+// Discriminator returns a RecordFunc for records whose shape depends on a key, like a "type" key.
+// It validates the record with the variant registered for the key's value, and reports an unknown
+// discriminator error listing the allowed values when there's no such variant
+func Discriminator(key string, variants map[string]*Validator) RecordFunc {
+	allowed := []string{}
+	for value := range variants {
+		allowed = append(allowed, value)
+	}
+	sort.Strings(allowed)
+
+	return func(values map[string]interface{}) (Response, error) {
+		r := Response{Errors: map[string][]string{}, IsValid: true}
+
+		value, ok := values[key]
+		if !ok {
+			r.AddError(key, "is required")
+			return r, nil
+		}
+
+		discriminator, ok := value.(string)
+		if !ok {
+			r.AddError(key, "must be a string")
+			return r, nil
+		}
+
+		variant, ok := variants[discriminator]
+		if !ok {
+			r.AddError(key, fmt.Sprintf("unknown discriminator %q, must be one of %s", discriminator, strings.Join(allowed, ", ")))
+			return r, nil
+		}
+
+		return variant.Validate(values)
+	}
+}
+
+// NestedDiscriminator returns a Func that validates a map[string]interface{} value like
+// Discriminator, reporting errors under the parent key
+func NestedDiscriminator(key string, variants map[string]*Validator) funcs.Func {
+	discriminate := Discriminator(key, variants)
+
+	meta := func(path []interface{}) funcs.Meta {
+		params := []funcs.Param{funcs.Param{Name: "key", Value: key}}
+		schemas := map[string]Description{}
+		for value, variant := range variants {
+			if levels := enclosing(path, variant); levels > 0 {
+				params = append(params, funcs.Param{Name: "enclosing." + value, Value: levels})
+				continue
+			}
+
+			schemas[value] = variant.describe(path)
+		}
+
+		sort.Slice(params[1:], func(i int, j int) bool { return params[i+1].Name < params[j+1].Name })
+
+		return funcs.Meta{Name: "NestedDiscriminator", Params: params, Schema: schemas}
+	}
+
+	return funcs.WithMetaPathFunc(meta, func(v interface{}) (funcs.Response, error) {
+		values, ok := v.(map[string]interface{})
+		if !ok {
+			return funcs.Response{IsValid: false, Error: "must be an object"}, nil
+		}
+
+		r, err := discriminate(values)
+		if err != nil {
+			return funcs.Response{}, err
+		}
+
+		return toFuncsResponse(r), nil
+	})
+}
@@ -0,0 +1,61 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nmante/validator/funcs"
+)
+
+func TestDiscriminator(t *testing.T) {
+	click, _ := New([]Rule{Rule{Key: "element_id", IsRequired: true}})
+	purchase, _ := New([]Rule{Rule{Key: "amount", IsRequired: true, Funcs: []funcs.Func{funcs.IsInt}}})
+	variants := map[string]*Validator{"click": click, "purchase": purchase}
+
+	event, _ := New(nil)
+	event.AddRecordRule(Discriminator("type", variants), false)
+
+	discriminatorTests := []struct {
+		values   map[string]interface{}
+		expected []FieldError
+	}{
+		{values: map[string]interface{}{"type": "click", "element_id": "buy"}, expected: []FieldError{}},
+		{values: map[string]interface{}{"type": "purchase", "amount": "10"}, expected: []FieldError{
+			FieldError{Key: "amount", Message: "must be a int"},
+		}},
+		{values: map[string]interface{}{"type": "scroll"}, expected: []FieldError{
+			FieldError{Key: "type", Message: `unknown discriminator "scroll", must be one of click, purchase`},
+		}},
+		{values: map[string]interface{}{}, expected: []FieldError{
+			FieldError{Key: "type", Message: "is required"},
+		}},
+	}
+
+	for _, test := range discriminatorTests {
+		r, err := event.Validate(test.values)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(r.FieldErrors(), test.expected) {
+			t.Errorf("Errors should be %+v. They are %+v", test.expected, r.FieldErrors())
+		}
+	}
+
+	batch, _ := New([]Rule{Rule{Key: "events", Funcs: []funcs.Func{funcs.Each(NestedDiscriminator("type", variants))}}})
+
+	r, err := batch.Validate(map[string]interface{}{
+		"events": []interface{}{
+			map[string]interface{}{"type": "click"},
+			map[string]interface{}{"type": "purchase", "amount": 5},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []FieldError{FieldError{Key: "events[0].element_id", Message: "is required"}}
+	if !reflect.DeepEqual(r.FieldErrors(), expected) {
+		t.Errorf("Errors should be %+v. They are %+v", expected, r.FieldErrors())
+	}
+}
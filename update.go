@@ -0,0 +1,142 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/nmante/validator/compare"
+	"github.com/nmante/validator/funcs"
+)
+
+// UpdateFunc checks how a key's value changed between a previous and a current record. A value is
+// nil if the key is missing from its record
+type UpdateFunc func(previous interface{}, current interface{}, previousValues map[string]interface{}, currentValues map[string]interface{}) (funcs.Response, error)
+
+type updateRule struct {
+	key   string
+	funcs []UpdateFunc
+}
+
+// AddUpdateRule adds UpdateFuncs for a key. They're run by ValidateUpdate
+func (v *Validator) AddUpdateRule(key string, fns ...UpdateFunc) *Validator {
+	for i, rule := range v.updateRules {
+		if rule.key == key {
+			v.updateRules[i].funcs = append(v.updateRules[i].funcs, fns...)
+			return v
+		}
+	}
+
+	v.updateRules = append(v.updateRules, updateRule{key: key, funcs: fns})
+	return v
+}
+
+// ValidateUpdate validates a current record like Validate, then checks how it changed from the
+// previous record with the update rules. Both records' keys are resolved like in Validate, so a
+// value sent under an alias or a differently normalized key is compared with the rule's key. Update
+// errors are reported under the changed keys, after the errors from Validate
+func (v *Validator) ValidateUpdate(previous map[string]interface{}, current map[string]interface{}) (Response, error) {
+	response, err := v.Validate(current)
+	if err != nil {
+		return Response{}, err
+	}
+
+	previous, _ = v.canonicalize(previous)
+	current, _ = v.canonicalize(current)
+
+	for _, rule := range v.updateRules {
+		for _, f := range rule.funcs {
+			r, err := f(previous[rule.key], current[rule.key], previous, current)
+			if err != nil {
+				return Response{}, err
+			}
+
+			if !r.IsValid {
+				response.add(r.Severity, rule.key, r.Error)
+			}
+		}
+	}
+
+	return response, nil
+}
+
+// Immutable checks that a value doesn't change or get removed once it's set
+func Immutable() UpdateFunc {
+	return func(previous interface{}, current interface{}, _ map[string]interface{}, _ map[string]interface{}) (funcs.Response, error) {
+		if previous == nil || reflect.DeepEqual(previous, current) {
+			return funcs.Response{IsValid: true}, nil
+		}
+
+		return funcs.Response{IsValid: false, Error: "must not change"}, nil
+	}
+}
+
+// Monotonic checks that a value never decreases, using comparer. Values comparer can't compare, like
+// float64s with compare.Int, aren't valid
+func Monotonic(comparer compare.Interface) UpdateFunc {
+	return func(previous interface{}, current interface{}, _ map[string]interface{}, _ map[string]interface{}) (funcs.Response, error) {
+		if previous == nil {
+			return funcs.Response{IsValid: true}, nil
+		}
+
+		if current == nil {
+			return funcs.Response{IsValid: false, Error: "must not be removed"}, nil
+		}
+
+		if reflect.TypeOf(current) != reflect.TypeOf(previous) {
+			return funcs.Response{IsValid: false, Error: fmt.Sprintf("must be a %T", previous)}, nil
+		}
+
+		result, err := compare.Try(comparer, current, previous)
+		if err != nil {
+			return funcs.Response{IsValid: false, Error: fmt.Sprintf("can't compare %T values", current)}, nil
+		}
+
+		if result < 0 {
+			return funcs.Response{IsValid: false, Error: fmt.Sprintf("must not decrease from %v", previous)}, nil
+		}
+
+		return funcs.Response{IsValid: true}, nil
+	}
+}
+
+// AllowedTransitions checks that a value only changes from one state to another when the change
+// is listed in transitions, like {"draft": {"submitted"}, "submitted": {"shipped"}}. A value that's
+// set can't be removed
+func AllowedTransitions[T comparable](transitions map[T][]T) UpdateFunc {
+	return func(previous interface{}, current interface{}, _ map[string]interface{}, _ map[string]interface{}) (funcs.Response, error) {
+		if previous == nil || reflect.DeepEqual(previous, current) {
+			return funcs.Response{IsValid: true}, nil
+		}
+
+		if current == nil {
+			return funcs.Response{IsValid: false, Error: "must not be removed"}, nil
+		}
+
+		from, fromOK := previous.(T)
+		to, toOK := current.(T)
+		if !fromOK || !toOK {
+			var zero T
+			return funcs.Response{IsValid: false, Error: fmt.Sprintf("must be a %T", zero)}, nil
+		}
+
+		for _, allowed := range transitions[from] {
+			if allowed == to {
+				return funcs.Response{IsValid: true}, nil
+			}
+		}
+
+		return funcs.Response{IsValid: false, Error: fmt.Sprintf("can't change from %v to %v", from, to)}, nil
+	}
+}
+
+// ChangedOnlyIf checks that a value only changes when predicate is true for the previous and current
+// records
+func ChangedOnlyIf(predicate func(previousValues map[string]interface{}, currentValues map[string]interface{}) bool, message string) UpdateFunc {
+	return func(previous interface{}, current interface{}, previousValues map[string]interface{}, currentValues map[string]interface{}) (funcs.Response, error) {
+		if reflect.DeepEqual(previous, current) || predicate(previousValues, currentValues) {
+			return funcs.Response{IsValid: true}, nil
+		}
+
+		return funcs.Response{IsValid: false, Error: message}, nil
+	}
+}
@@ -0,0 +1,75 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/nmante/validator/compare"
+)
+
+func TestValidateUpdate(t *testing.T) {
+	validator, _ := New([]Rule{Rule{Key: "status", IsRequired: true}})
+	validator.
+		AddUpdateRule("created_by", Immutable()).
+		AddUpdateRule("version", Monotonic(compare.Int)).
+		AddUpdateRule("status", AllowedTransitions(map[string][]string{
+			"draft":     {"submitted"},
+			"submitted": {"shipped", "draft"},
+		})).
+		AddUpdateRule("price", ChangedOnlyIf(func(previous map[string]interface{}, current map[string]interface{}) bool {
+			return current["status"] == "draft"
+		}, "can only change in draft"))
+
+	previous := map[string]interface{}{"created_by": "ann", "version": 2, "status": "draft", "price": 10}
+
+	updateTests := []struct {
+		current  map[string]interface{}
+		expected []FieldError
+	}{
+		{current: map[string]interface{}{"created_by": "ann", "version": 3, "status": "submitted", "price": 10}, expected: []FieldError{}},
+		{current: map[string]interface{}{"created_by": "ann", "version": 2, "status": "draft", "price": 12}, expected: []FieldError{}},
+		{current: map[string]interface{}{"created_by": "bob", "version": 1, "status": "shipped", "price": 12}, expected: []FieldError{
+			FieldError{Key: "created_by", Message: "must not change"},
+			FieldError{Key: "version", Message: "must not decrease from 2"},
+			FieldError{Key: "status", Message: "can't change from draft to shipped"},
+			FieldError{Key: "price", Message: "can only change in draft"},
+		}},
+		{current: map[string]interface{}{"version": 2, "price": 10}, expected: []FieldError{
+			FieldError{Key: "status", Message: "is required"},
+			FieldError{Key: "status", Message: "must not be removed"},
+			FieldError{Key: "created_by", Message: "must not change"},
+		}},
+		{current: map[string]interface{}{"created_by": "ann", "version": 3.0, "status": "draft", "price": 10}, expected: []FieldError{
+			FieldError{Key: "version", Message: "must be a int"},
+		}},
+	}
+
+	for i, test := range updateTests {
+		r, err := validator.ValidateUpdate(previous, test.current)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(r.FieldErrors(), test.expected) {
+			t.Errorf("Test %d: errors should be %+v. They are %+v", i, test.expected, r.FieldErrors())
+		}
+	}
+
+	r, _ := Monotonic(compare.Int)(1.0, 2.0, nil, nil)
+	if r.IsValid || r.Error != "can't compare float64 values" {
+		t.Errorf("Monotonic should fail on values its comparer can't compare. Response is %+v", r)
+	}
+
+	aliased, _ := New([]Rule{Rule{Key: "created_by", Aliases: []string{"author"}}}, OptionKeyNormalization(KeyFoldCase))
+	aliased.AddUpdateRule("created_by", Immutable())
+
+	r2, err := aliased.ValidateUpdate(map[string]interface{}{"Created_By": "ann"}, map[string]interface{}{"author": "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []FieldError{FieldError{Key: "created_by", Message: "must not change"}}
+	if !reflect.DeepEqual(r2.FieldErrors(), expected) {
+		t.Errorf("Keys should be resolved before comparing. Errors should be %+v. They are %+v", expected, r2.FieldErrors())
+	}
+}
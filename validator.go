@@ -1,39 +1,58 @@
 package validator
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+
 	"github.com/nmante/validator/funcs"
 )
 
+var (
+	ErrConflictingRules = errors.New("Rules with the same key must not have different settings")
+)
+
 // Validator is an object that contains a set of rules that can be validated in parallel, or synchronously
 type Validator struct {
-	enableParallel bool
-	rules          map[string]Rule
-}
-
-// Response contains a bool for if all rules are valid, as well as error messages for invalid rules
-type Response struct {
-	Errors  map[string][]string
-	IsValid bool
+	enableParallel  bool
+	failFast        bool
+	detailed        bool
+	selfValidation  bool
+	rules           []Rule
+	index           map[string]int
+	recordRules     []recordRule
+	patternRules    []PatternRule
+	updateRules     []updateRule
+	normalization   KeyNormalization
+	mapDeprecated   bool
+	deprecationHook DeprecationHook
 }
 
-// New returns a validator object
+// New returns a validator object. Rules with the same key are merged, so their Funcs and Aliases are
+// added to the first rule with the key, and their other fields are ignored. A later rule can only
+// set Groups and RequiredFor to the same values as the first one; otherwise New returns
+// ErrConflictingRules
 func New(rules []Rule, options ...Option) (*Validator, error) {
-	rs := map[string]Rule{}
+	v := &Validator{
+		enableParallel: false,
+		selfValidation: false,
+		rules:          []Rule{},
+		index:          map[string]int{},
+	}
 
 	for _, rule := range rules {
-		if _, ok := rs[rule.Key]; !ok {
-			rs[rule.Key] = rule
+		if i, ok := v.index[rule.Key]; ok {
+			if !isMergeable(v.rules[i], rule) {
+				return nil, fmt.Errorf("%w: %s", ErrConflictingRules, rule.Key)
+			}
+
+			v.rules[i].Funcs = append(v.rules[i].Funcs, rule.Funcs...)
+			v.rules[i].Aliases = append(v.rules[i].Aliases, rule.Aliases...)
 			continue
 		}
 
-		r := rs[rule.Key]
-		r.Funcs = append(r.Funcs, rule.Funcs...)
-		rs[rule.Key] = r
-	}
-
-	v := &Validator{
-		enableParallel: false,
-		rules:          rs,
+		v.index[rule.Key] = len(v.rules)
+		v.rules = append(v.rules, rule)
 	}
 
 	for _, option := range options {
@@ -47,43 +66,91 @@ func New(rules []Rule, options ...Option) (*Validator, error) {
 	return v, nil
 }
 
+// isMergeable checks if rule's Funcs can run for existing's scenarios, because its Groups and
+// RequiredFor are unset or the same as existing's
+func isMergeable(existing Rule, rule Rule) bool {
+	if len(rule.Groups) == 0 && len(rule.RequiredFor) == 0 {
+		return true
+	}
+
+	return reflect.DeepEqual(rule.Groups, existing.Groups) && reflect.DeepEqual(rule.RequiredFor, existing.RequiredFor)
+}
+
 // AddRule adds a rule to the validator
 func (v *Validator) AddRule(key string, funcs ...funcs.Func) *Validator {
-	if rule, ok := v.rules[key]; ok {
-		rule.Funcs = append(rule.Funcs, funcs...)
-		v.rules[key] = rule
+	if i, ok := v.index[key]; ok {
+		v.rules[i].Funcs = append(v.rules[i].Funcs, funcs...)
 		return v
 	}
 
-	v.rules[key] = Rule{Key: key, Funcs: funcs}
+	v.index[key] = len(v.rules)
+	v.rules = append(v.rules, Rule{Key: key, Funcs: funcs})
 	return v
 }
 
 // Rules returns the map of rules for this validator object
 func (v *Validator) Rules() map[string]Rule {
-	return v.rules
+	rules := map[string]Rule{}
+	for _, rule := range v.rules {
+		rules[rule.Key] = rule
+	}
+
+	return rules
+}
+
+// OrderedRules returns the rules for this validator object in the order they were registered
+func (v *Validator) OrderedRules() []Rule {
+	rules := make([]Rule, len(v.rules))
+	copy(rules, v.rules)
+
+	return rules
 }
 
-// Validate runs all the rules of validation
+// Validate runs all the rules of validation. Errors are reported in rule registration order, then
+// func order within a rule, whether or not rules run in parallel. Pattern rules come after exact
+// rules, by pattern then key. Values that implement Validatable or funcs.Interface are validated
+// too, before their key's rule. Those without an exact rule come last, by key. Keys matching a rule's
+// aliases, or matching its key after normalization, are validated and reported under the rule's key
 func (v *Validator) Validate(values map[string]interface{}) (Response, error) {
-	errors := map[string][]string{}
-	isValid := true
+	response := Response{
+		Errors:   map[string][]string{},
+		IsValid:  true,
+		Skipped:  []string{},
+		Warnings: map[string][]string{},
+		Infos:    map[string][]string{},
+	}
 	jobs := []Job{}
 
-	for key, rule := range v.Rules() {
-		if value, ok := values[key]; ok {
-			rj, err := NewRuleJob(value, rule)
-			if err != nil {
-				return Response{}, err
-			}
+	values, conflicts := v.canonicalize(values)
+	v.addConflicts(conflicts, &response)
 
-			jobs = append(jobs, rj)
+	if v.mapDeprecated {
+		values = v.addReplacements(values)
+	}
+
+	var c *canceler
+	if v.failFast {
+		c = newCanceler()
+	}
+
+	runs := v.ruleRuns(values)
+	for _, run := range runs {
+		if !run.present {
+			if run.rule.IsRequired && run.rule.Severity == funcs.SeverityError {
+				c.cancel()
+			}
+			continue
 		}
 
-		if _, ok := values[key]; rule.IsRequired && !ok {
-			errors[key] = []string{"is required"}
-			isValid = false
+		rule := run.rule
+		rule.Detailed = rule.Detailed || v.detailed
+		rj, err := NewRuleJob(run.value, rule, withRuleCanceler(c))
+		if err != nil {
+			return Response{}, err
 		}
+
+		run.job = rj
+		jobs = append(jobs, rj)
 	}
 
 	if v.enableParallel {
@@ -94,27 +161,97 @@ func (v *Validator) Validate(values map[string]interface{}) (Response, error) {
 		pool.Run()
 	}
 
-	for _, job := range jobs {
-		j, ok := job.(*RuleJob)
-		if !ok {
-			return Response{}, ErrMustBeRuleJob
+	for _, run := range runs {
+		rule := run.rule
+
+		if run.exact && run.present {
+			v.reportDeprecations(rule, run.value, &response)
+		}
+
+		if run.exact && run.present && v.selfValidation {
+			if err := selfValidate(rule.Key, run.value, &response); err != nil {
+				return Response{}, err
+			}
+		}
+
+		j := run.job
+		if j == nil {
+			if rule.IsRequired {
+				response.add(rule.Severity, rule.Key, "is required")
+			}
+			continue
 		}
 
 		if !v.enableParallel {
-			response, err := j.rule.execute(j.value)
-			j.Err = err
-			j.Result = response
+			j.run()
+		}
+
+		if j.Skipped {
+			response.Skipped = append(response.Skipped, rule.Key)
+			continue
 		}
 
 		if j.Err != nil {
 			return Response{}, j.Err
 		}
 
-		if !j.Result.IsValid {
-			errors[j.rule.Key] = append(errors[j.rule.Key], j.Result.ValidationErrors...)
-			isValid = false
+		if j.rule.Detailed {
+			response.Details = append(response.Details, j.Result)
+		}
+
+		if len(j.Result.ValidationErrors) > 0 {
+			response.AddError(rule.Key, j.Result.ValidationErrors...)
+		}
+
+		for _, pe := range j.Result.NestedErrors {
+			response.AddError(joinKey(rule.Key, pe.Path), pe.Error)
+		}
+
+		for _, pe := range j.Result.Warnings {
+			response.AddWarning(joinKey(rule.Key, pe.Path), pe.Error)
 		}
+
+		for _, pe := range j.Result.Infos {
+			response.AddInfo(joinKey(rule.Key, pe.Path), pe.Error)
+		}
+	}
+
+	v.validatePatternMatches(values, &response)
+
+	if v.selfValidation {
+		if err := v.selfValidateUnruled(values, &response); err != nil {
+			return Response{}, err
+		}
+	}
+
+	if err := v.validateRecord(values, &response); err != nil {
+		return Response{}, err
+	}
+
+	if v.mapDeprecated {
+		response.Values = v.renameDeprecated(values)
+	}
+
+	return response, nil
+}
+
+// ruleRun is a rule to run against an input value
+type ruleRun struct {
+	rule    Rule
+	value   interface{}
+	present bool
+	exact   bool
+	job     *RuleJob
+}
+
+// ruleRuns returns the exact rules in registration order, followed by the pattern rules matching
+// keys that have no exact rule
+func (v *Validator) ruleRuns(values map[string]interface{}) []*ruleRun {
+	runs := []*ruleRun{}
+	for _, rule := range v.rules {
+		value, ok := values[rule.Key]
+		runs = append(runs, &ruleRun{rule: rule, value: value, present: ok, exact: true})
 	}
 
-	return Response{Errors: errors, IsValid: isValid}, nil
+	return append(runs, v.patternRuns(values)...)
 }
@@ -3,6 +3,7 @@ package validator
 import (
 	"errors"
 	"reflect"
+	"regexp"
 	"testing"
 	"time"
 
@@ -20,9 +21,9 @@ func TestNew(t *testing.T) {
 
 	mustBeOne := func(v interface{}) (funcs.Response, error) {
 		if v.(int) != 1 {
-			return funcs.Response{false, "must be 1"}, nil
+			return funcs.Response{IsValid: false, Error: "must be 1"}, nil
 		}
-		return funcs.Response{true, ""}, nil
+		return funcs.Response{IsValid: true, Error: ""}, nil
 	}
 
 	v2, _ := New([]Rule{Rule{Key: "random", Funcs: []funcs.Func{mustBeOne}}})
@@ -43,10 +44,10 @@ func TestValidate(t *testing.T) {
 		}
 
 		if val%2 != 0 {
-			return funcs.Response{false, "must be even integer"}, nil
+			return funcs.Response{IsValid: false, Error: "must be even integer"}, nil
 		}
 
-		return funcs.Response{true, ""}, nil
+		return funcs.Response{IsValid: true, Error: ""}, nil
 	})
 
 	validator.AddRule("page_size", funcs.String.IsInt)
@@ -98,10 +99,10 @@ func TestAddRule(t *testing.T) {
 		}
 
 		if val%2 == 0 {
-			return funcs.Response{false, "must be an odd integer"}, nil
+			return funcs.Response{IsValid: false, Error: "must be an odd integer"}, nil
 		}
 
-		return funcs.Response{true, ""}, nil
+		return funcs.Response{IsValid: true, Error: ""}, nil
 	}).AddRule("must_be_odd", func(v interface{}) (funcs.Response, error) {
 		val, ok := v.(int)
 		if !ok {
@@ -109,10 +110,10 @@ func TestAddRule(t *testing.T) {
 		}
 
 		if val%2 == 0 {
-			return funcs.Response{false, "must be an odd integer"}, nil
+			return funcs.Response{IsValid: false, Error: "must be an odd integer"}, nil
 		}
 
-		return funcs.Response{true, ""}, nil
+		return funcs.Response{IsValid: true, Error: ""}, nil
 	})
 
 	if n := len(validator.Rules()); n != 1 {
@@ -135,13 +136,13 @@ func TestParallelPropertyValidation(t *testing.T) {
 	isVideoExists := func(v interface{}) (funcs.Response, error) {
 		// Simulate long processing video processing task
 		time.Sleep(numMillisecondsBlock * time.Millisecond)
-		return funcs.Response{true, ""}, nil
+		return funcs.Response{IsValid: true, Error: ""}, nil
 	}
 
 	isImageExists := func(v interface{}) (funcs.Response, error) {
 		// Simulate long processing image task
 		time.Sleep(numMillisecondsBlock * time.Millisecond)
-		return funcs.Response{true, ""}, nil
+		return funcs.Response{IsValid: true, Error: ""}, nil
 	}
 
 	// Call the isVideoExists function twice just to show both funcs process in parallel
@@ -191,7 +192,7 @@ func TestParallelPropertyValidation(t *testing.T) {
 	timeDuration := time.Since(startTime)
 
 	if err != nil {
-		t.Errorf(err.Error())
+		t.Error(err)
 	}
 
 	if !r.IsValid {
@@ -205,3 +206,444 @@ func TestParallelPropertyValidation(t *testing.T) {
 		t.Errorf("Test should be between %v & %v. Actually took %v milliseconds", min, max, timeDuration)
 	}
 }
+
+func TestRuleBail(t *testing.T) {
+	calls := 0
+	fail := func(v interface{}) (funcs.Response, error) {
+		calls++
+		return funcs.Response{IsValid: false, Error: "always fails"}, nil
+	}
+
+	validator, _ := New([]Rule{
+		Rule{Key: "sequential", Bail: true, Funcs: []funcs.Func{fail, fail, fail}},
+	})
+
+	r, err := validator.Validate(map[string]interface{}{"sequential": 1})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if calls != 1 {
+		t.Errorf("Bail should stop after the first failure. %d funcs were called", calls)
+	}
+
+	if n := len(r.Errors["sequential"]); n != 1 {
+		t.Errorf("There should be 1 error. There are %d", n)
+	}
+
+	slow := func(v interface{}) (funcs.Response, error) {
+		time.Sleep(50 * time.Millisecond)
+		return funcs.Response{IsValid: true}, nil
+	}
+
+	validator, _ = New([]Rule{
+		Rule{Key: "parallel", Bail: true, EnableParallel: true, Funcs: []funcs.Func{fail, slow, fail}},
+	})
+
+	r, err = validator.Validate(map[string]interface{}{"parallel": 1})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if r.IsValid || len(r.Errors["parallel"]) != 1 {
+		t.Errorf("Only the first parallel failure should be reported, %+v", r)
+	}
+}
+
+func TestFailFast(t *testing.T) {
+	for _, isParallel := range []bool{false, true} {
+		validator, _ := New(
+			[]Rule{
+				Rule{Key: "id", IsRequired: true, Funcs: []funcs.Func{funcs.IsInt}},
+				Rule{Key: "name", Funcs: []funcs.Func{funcs.IsInt}},
+				Rule{Key: "count", Funcs: []funcs.Func{funcs.IsInt}},
+			},
+			OptionParallel(isParallel),
+			OptionFailFast(true),
+		)
+
+		if validator.enableParallel != isParallel {
+			t.Errorf("Parallel should be %t", isParallel)
+		}
+
+		r, err := validator.Validate(map[string]interface{}{
+			"name":  "not an int",
+			"count": "not an int",
+		})
+		if err != nil {
+			t.Error(err)
+		}
+
+		if r.IsValid {
+			t.Error("Validation should fail")
+		}
+
+		if len(r.Skipped) != 2 {
+			t.Errorf("Both rules should be skipped after 'id' fails. Skipped: %v", r.Skipped)
+		}
+	}
+
+	// run sequentially, the rules after the first invalid one are skipped without being run
+	calls := 0
+	counted := func(v interface{}) (funcs.Response, error) {
+		calls++
+		return funcs.Response{IsValid: true}, nil
+	}
+
+	validator, _ := New(
+		[]Rule{
+			Rule{Key: "id", Funcs: []funcs.Func{funcs.IsInt}},
+			Rule{Key: "name", Funcs: []funcs.Func{counted}},
+			Rule{Key: "count", Funcs: []funcs.Func{counted}},
+		},
+		OptionParallel(false),
+		OptionFailFast(true),
+	)
+
+	r, err := validator.Validate(map[string]interface{}{"id": "not an int", "name": "Ann", "count": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 0 || !reflect.DeepEqual(r.Skipped, []string{"name", "count"}) {
+		t.Errorf("name and count should be skipped without running. Calls: %d, skipped: %v", calls, r.Skipped)
+	}
+}
+
+func TestOrderedErrors(t *testing.T) {
+	mustBe := func(message string) funcs.Func {
+		return func(v interface{}) (funcs.Response, error) {
+			return funcs.Response{IsValid: false, Error: message}, nil
+		}
+	}
+
+	keys := []string{"zeta", "alpha", "mu", "beta", "omega"}
+	rules := []Rule{}
+	for _, key := range keys {
+		rules = append(rules, Rule{Key: key, EnableParallel: true, Funcs: []funcs.Func{mustBe("first"), mustBe("second")}})
+	}
+
+	values := map[string]interface{}{}
+	for _, key := range keys {
+		values[key] = 1
+	}
+
+	validator, _ := New(rules, OptionParallel(true))
+
+	for i, rule := range validator.OrderedRules() {
+		if rule.Key != keys[i] {
+			t.Errorf("Rule %d should be %s. It is %s", i, keys[i], rule.Key)
+		}
+	}
+
+	for run := 0; run < 10; run++ {
+		r, err := validator.Validate(values)
+		if err != nil {
+			t.Error(err)
+		}
+
+		fieldErrors := r.FieldErrors()
+		if len(fieldErrors) != 2*len(keys) {
+			t.Fatalf("There should be %d errors. There are %d", 2*len(keys), len(fieldErrors))
+		}
+
+		for i, fe := range fieldErrors {
+			expected := FieldError{Key: keys[i/2], Message: []string{"first", "second"}[i%2]}
+			if fe != expected {
+				t.Errorf("Error %d should be %+v. It is %+v", i, expected, fe)
+			}
+		}
+	}
+}
+
+func TestDetailedResults(t *testing.T) {
+	validator, _ := New(
+		[]Rule{
+			Rule{Key: "page_size", Funcs: []funcs.Func{funcs.IsInt, funcs.String.IsInt, funcs.IsLength(2)}},
+		},
+		OptionDetailedResults(true),
+	)
+
+	r, err := validator.Validate(map[string]interface{}{"page_size": "100"})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if n := len(r.Errors["page_size"]); n != 2 {
+		t.Errorf("Only failing funcs should add errors. There are %d errors: %q", n, r.Errors["page_size"])
+	}
+
+	if len(r.Details) != 1 {
+		t.Fatalf("There should be 1 detailed rule response. There are %d", len(r.Details))
+	}
+
+	expected := []FuncResult{
+		FuncResult{Index: 0, Name: "IsInt", IsValid: false, Message: "must be a int"},
+		FuncResult{Index: 1, Name: "String.IsInt", IsValid: true},
+		FuncResult{Index: 2, Name: "IsLength", IsValid: false, Message: "Must have length 2"},
+	}
+
+	for i, result := range r.Details[0].Results {
+		result.Duration = 0
+		if result != expected[i] {
+			t.Errorf("Func result should be %+v. It is %+v", expected[i], result)
+		}
+	}
+}
+
+func TestRecordRules(t *testing.T) {
+	sumsToTotal := func(values map[string]interface{}) (Response, error) {
+		r := Response{IsValid: true}
+
+		sum := 0
+		for _, item := range values["items"].([]int) {
+			sum += item
+		}
+
+		if sum != values["total"].(int) {
+			r.AddError("total", "must equal the sum of items")
+		}
+
+		return r, nil
+	}
+
+	atMostOneFlag := func(values map[string]interface{}) (Response, error) {
+		r := Response{IsValid: true}
+		if values["gift"] == true && values["express"] == true {
+			r.AddError(FormKey, "only one of gift and express may be set")
+		}
+
+		return r, nil
+	}
+
+	validator, _ := New([]Rule{Rule{Key: "total", IsRequired: true, Funcs: []funcs.Func{funcs.IsInt}}})
+	validator.AddRecordRule(sumsToTotal, true).AddRecordRule(atMostOneFlag, false)
+
+	r, err := validator.Validate(map[string]interface{}{
+		"items":   []int{1, 2, 3},
+		"total":   7,
+		"gift":    true,
+		"express": true,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := []FieldError{
+		FieldError{Key: "total", Message: "must equal the sum of items"},
+		FieldError{Key: FormKey, Message: "only one of gift and express may be set"},
+	}
+
+	if !reflect.DeepEqual(r.FieldErrors(), expected) {
+		t.Errorf("Errors should be %+v. They are %+v", expected, r.FieldErrors())
+	}
+
+	r, err = validator.Validate(map[string]interface{}{"gift": true, "express": true})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if _, ok := r.Errors[FormKey]; !ok || len(r.Errors) != 2 {
+		t.Errorf("Only record rules that don't need valid keys should run, %+v", r.Errors)
+	}
+
+	validator, _ = New([]Rule{})
+	validator.AddRecordRule(func(values map[string]interface{}) (Response, error) {
+		return Response{}, nil
+	}, false)
+
+	if r, _ := validator.Validate(map[string]interface{}{}); !r.IsValid {
+		t.Errorf("A zero Response without errors should pass. Errors are %+v", r.Errors)
+	}
+}
+
+func TestResponseMerge(t *testing.T) {
+	child := Response{IsValid: true}
+	child.AddError("city", "is required")
+	child.AddError("[2]", "must be a string")
+	child.AddError(FormKey, "is not deliverable")
+
+	r := Response{IsValid: true}
+	r.Merge("shipping_address", child)
+
+	expected := []FieldError{
+		FieldError{Key: "shipping_address.city", Message: "is required"},
+		FieldError{Key: "shipping_address[2]", Message: "must be a string"},
+		FieldError{Key: "shipping_address", Message: "is not deliverable"},
+	}
+
+	if r.IsValid || !reflect.DeepEqual(r.FieldErrors(), expected) {
+		t.Errorf("Errors should be %+v. They are %+v", expected, r.FieldErrors())
+	}
+}
+
+type email string
+
+func (e email) Validate() (funcs.Response, error) {
+	return funcs.String.IsEmail(string(e))
+}
+
+type money struct {
+	Amount   int
+	Currency string
+}
+
+func (m money) Validate() (Response, error) {
+	r := Response{IsValid: true}
+	if m.Amount < 0 {
+		r.AddError("amount", "must not be negative")
+	}
+
+	if len(m.Currency) != 3 {
+		r.AddError("currency", "must be an ISO 4217 code")
+	}
+
+	return r, nil
+}
+
+func TestSelfValidation(t *testing.T) {
+	validator, _ := New([]Rule{Rule{Key: "price", IsRequired: true}}, OptionSelfValidation(true))
+
+	values := map[string]interface{}{
+		"price":   money{Amount: -1, Currency: "USD"},
+		"contact": email("hello"),
+		"total":   money{Amount: 1, Currency: "US"},
+	}
+
+	r, err := validator.Validate(values)
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := []FieldError{
+		FieldError{Key: "price.amount", Message: "must not be negative"},
+		FieldError{Key: "contact", Message: "Must be an email address"},
+		FieldError{Key: "total.currency", Message: "must be an ISO 4217 code"},
+	}
+
+	if !reflect.DeepEqual(r.FieldErrors(), expected) {
+		t.Errorf("Errors should be %+v. They are %+v", expected, r.FieldErrors())
+	}
+
+	validator, _ = New([]Rule{Rule{Key: "price", IsRequired: true}})
+
+	r, err = validator.Validate(values)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !r.IsValid {
+		t.Errorf("Values shouldn't validate themselves by default, %+v", r.Errors)
+	}
+}
+
+func TestElementErrors(t *testing.T) {
+	validator, _ := New([]Rule{
+		Rule{Key: "tags", Funcs: []funcs.Func{funcs.IsLengthBetween(1, 5), funcs.Each(funcs.IsLengthBetween(1, 3)), funcs.Unique}},
+	})
+
+	r, err := validator.Validate(map[string]interface{}{"tags": []string{"go", "rust", "go"}})
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := []FieldError{
+		FieldError{Key: "tags[1]", Message: "Must be between length 1 and 3"},
+		FieldError{Key: "tags[2]", Message: "must be unique, duplicates [0]"},
+	}
+
+	if !reflect.DeepEqual(r.FieldErrors(), expected) {
+		t.Errorf("Errors should be %+v. They are %+v", expected, r.FieldErrors())
+	}
+}
+
+func TestPatternRules(t *testing.T) {
+	validator, _ := New([]Rule{Rule{Key: "meta_id", Funcs: []funcs.Func{funcs.IsInt}}})
+
+	if err := validator.AddPatternRule(PatternRule{Pattern: "meta_*", Funcs: []funcs.Func{funcs.IsLengthBetween(1, 3)}, MinMatches: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := validator.AddPatternRule(PatternRule{Regexp: regexp.MustCompile(`^(?i)x-`), MaxMatches: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := validator.AddPatternRule(PatternRule{Pattern: "[a-"}); err == nil {
+		t.Error("Bad patterns should be an error")
+	}
+
+	r, err := validator.Validate(map[string]interface{}{
+		"meta_id":    1,
+		"meta_color": "red",
+		"meta_size":  "extra large",
+		"X-Trace":    "a",
+		"x-span":     "b",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []FieldError{
+		FieldError{Key: "meta_size", Message: "Must be between length 1 and 3"},
+		FieldError{Key: "^(?i)x-", Message: "at most 1 keys may match"},
+	}
+
+	if !reflect.DeepEqual(r.FieldErrors(), expected) {
+		t.Errorf("Errors should be %+v. They are %+v", expected, r.FieldErrors())
+	}
+
+	r, _ = validator.Validate(map[string]interface{}{"meta_id": 1})
+	if m := r.Errors["meta_*"]; len(m) != 1 || m[0] != "at least 3 keys must match" {
+		t.Errorf("There should be a minimum matches error. Errors: %+v", r.Errors)
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	address, _ := New([]Rule{Rule{Key: "zip", Funcs: []funcs.Func{funcs.Warning(funcs.IsLength(5))}}})
+
+	validator, _ := New([]Rule{
+		Rule{Key: "age", Funcs: []funcs.Func{funcs.Warning(funcs.IsInt), funcs.IsInt}, Bail: true},
+		Rule{Key: "name", Funcs: []funcs.Func{funcs.IsLengthBetween(1, 3)}, Severity: funcs.SeverityInfo},
+		Rule{Key: "address", Funcs: []funcs.Func{Nested(address)}},
+	})
+
+	r, err := validator.Validate(map[string]interface{}{
+		"age":     "old",
+		"name":    "Annabel",
+		"address": map[string]interface{}{"zip": "123"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedErrors := []FieldError{FieldError{Key: "age", Message: "must be a int"}}
+	expectedWarnings := []FieldError{
+		FieldError{Key: "age", Message: "must be a int"},
+		FieldError{Key: "address.zip", Message: "Must have length 5"},
+	}
+	expectedInfos := []FieldError{FieldError{Key: "name", Message: "Must be between length 1 and 3"}}
+
+	if r.IsValid || !reflect.DeepEqual(r.FieldErrors(), expectedErrors) {
+		t.Errorf("Errors should be %+v. They are %+v", expectedErrors, r.FieldErrors())
+	}
+
+	if !reflect.DeepEqual(r.FieldWarnings(), expectedWarnings) {
+		t.Errorf("Warnings should be %+v. They are %+v", expectedWarnings, r.FieldWarnings())
+	}
+
+	if !reflect.DeepEqual(r.FieldInfos(), expectedInfos) {
+		t.Errorf("Infos should be %+v. They are %+v", expectedInfos, r.FieldInfos())
+	}
+
+	r, _ = validator.Validate(map[string]interface{}{"age": 30, "name": "Annabel"})
+	if !r.IsValid || len(r.Infos) != 1 {
+		t.Errorf("Response should be valid with an info. It is %+v", r)
+	}
+
+	validator, _ = New([]Rule{Rule{Key: "nickname", IsRequired: true, Severity: funcs.SeverityWarning}})
+	r, _ = validator.Validate(map[string]interface{}{})
+	expectedWarnings = []FieldError{FieldError{Key: "nickname", Message: "is required"}}
+	if !r.IsValid || !reflect.DeepEqual(r.FieldWarnings(), expectedWarnings) {
+		t.Errorf("Response should be valid with warnings %+v. It is %+v", expectedWarnings, r)
+	}
+}